@@ -10,12 +10,54 @@ import "github.com/gvallee/go_software_build/pkg/app"
 
 // Config represents the static OSU configuration (what never changes at runtime)
 type Config struct {
+	// Name is the name of the benchmark, e.g., osu
+	Name string
+
+	// Version is the version of the benchmark, e.g., 5.8
+	Version string
+
 	URL string
 
+	// Mirrors is a list of alternative URLs to use when URL is not reachable
+	Mirrors []string
+
 	Tarball string
+
+	// SHA256 is the expected SHA-256 checksum of the tarball, as a hexadecimal string
+	SHA256 string
+}
+
+// String returns a stable identifier of the benchmark based on its name and version
+func (c *Config) String() string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "-" + c.Version
 }
 
 // Install gathers all the data regarding the installation of OSU so it can easily be looked up later on
 type Install struct {
 	SubBenchmarks []app.Info
 }
+
+// DataPoint represents a single measurement of a benchmark, e.g., the latency for a given message size
+type DataPoint struct {
+	// Size is the message size in bytes
+	Size float64
+
+	// Value is the measured value for the message size (latency, bandwidth...)
+	Value float64
+
+	// Extra are optional additional values for the message size, e.g., the minimum and maximum latencies
+	Extra map[string]float64
+}
+
+// Result represents the data points of a single run of a benchmark
+type Result struct {
+	DataPoints []*DataPoint
+}
+
+// Results gathers the results of multiple runs of a benchmark
+type Results struct {
+	Result []*Result
+}
@@ -0,0 +1,246 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Metric is the kind of measurement of a benchmark, which defines whether lower or higher values are better
+type Metric int
+
+const (
+	// Latency is a metric for which lower values are better
+	Latency Metric = iota
+
+	// Bandwidth is a metric for which higher values are better
+	Bandwidth
+)
+
+// String returns the name of the metric
+func (m Metric) String() string {
+	switch m {
+	case Latency:
+		return "latency"
+	case Bandwidth:
+		return "bandwidth"
+	}
+	return fmt.Sprintf("Metric(%d)", int(m))
+}
+
+func (m Metric) check() error {
+	if m != Latency && m != Bandwidth {
+		return fmt.Errorf("unsupported metric %s", m)
+	}
+	return nil
+}
+
+// alignBySize returns the values of the comparison indexed by size after making sure that both the baseline
+// and the comparison have exactly the same sizes
+func alignBySize(baseline, comparison *Result) (map[float64]float64, error) {
+	if baseline == nil || comparison == nil {
+		return nil, fmt.Errorf("undefined result")
+	}
+
+	comparisonValues := make(map[float64]float64)
+	for _, dp := range comparison.DataPoints {
+		comparisonValues[dp.Size] = dp.Value
+	}
+	baselineSizes := make(map[float64]bool)
+	for _, dp := range baseline.DataPoints {
+		baselineSizes[dp.Size] = true
+		if _, ok := comparisonValues[dp.Size]; !ok {
+			return nil, fmt.Errorf("size %f is missing from the comparison", dp.Size)
+		}
+	}
+	for _, dp := range comparison.DataPoints {
+		if !baselineSizes[dp.Size] {
+			return nil, fmt.Errorf("size %f is missing from the baseline", dp.Size)
+		}
+	}
+	return comparisonValues, nil
+}
+
+// SpeedupRatio returns a result where the value for each size is the speedup of the comparison over the baseline,
+// i.e., a value greater than 1 is an improvement. For latency, it is the ratio between the value of the baseline
+// and the value of the comparison; for bandwidth, the ratio between the value of the comparison and the value of
+// the baseline. Data points are aligned by size, not by index. A zero divisor, i.e., a zero latency for the
+// comparison or a zero bandwidth for the baseline, is reported as an error.
+func SpeedupRatio(baseline, comparison *Result, metric Metric) (*Result, error) {
+	err := metric.check()
+	if err != nil {
+		return nil, err
+	}
+	comparisonValues, err := alignBySize(baseline, comparison)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute speedup ratio: %w", err)
+	}
+
+	ratio := new(Result)
+	for _, dp := range baseline.DataPoints {
+		numerator, divisor, divisorName := dp.Value, comparisonValues[dp.Size], "comparison"
+		if metric == Bandwidth {
+			numerator, divisor, divisorName = divisor, numerator, "baseline"
+		}
+		if divisor == 0 {
+			return nil, fmt.Errorf("unable to compute speedup ratio: zero %s of the %s for size %f", metric, divisorName, dp.Size)
+		}
+		value := numerator / divisor
+		ratio.DataPoints = append(ratio.DataPoints, &DataPoint{Size: dp.Size, Value: value})
+	}
+	return ratio, nil
+}
+
+// RegressionDelta is the difference between a baseline and a comparison for a given size
+type RegressionDelta struct {
+	// Size is the message size
+	Size float64
+
+	// Baseline is the value of the baseline
+	Baseline float64
+
+	// Comparison is the value of the comparison
+	Comparison float64
+
+	// ChangePct is the relative change of the comparison compared to the baseline, in percent
+	ChangePct float64
+
+	// Regression specifies whether the change is a regression above the threshold
+	Regression bool
+}
+
+// RegressionReport is the outcome of the evaluation of a comparison against a baseline
+type RegressionReport struct {
+	// Failed specifies whether at least one size regressed above the threshold
+	Failed bool
+
+	// Deltas are the differences between the baseline and the comparison, one per size
+	Deltas []*RegressionDelta
+
+	// Worst is the delta with the most significant regression, nil if there is no regression
+	Worst *RegressionDelta
+}
+
+// EvaluateRegressions compares a result against a baseline and reports the sizes for which the comparison
+// is worse than the baseline by more than thresholdPct percents. The metric specifies whether lower or higher
+// values are better.
+func EvaluateRegressions(baseline, comparison *Result, thresholdPct float64, metric Metric) (*RegressionReport, error) {
+	err := metric.check()
+	if err != nil {
+		return nil, err
+	}
+	if thresholdPct < 0 {
+		return nil, fmt.Errorf("invalid threshold %f, must not be negative", thresholdPct)
+	}
+	comparisonValues, err := alignBySize(baseline, comparison)
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate regressions: %w", err)
+	}
+
+	report := new(RegressionReport)
+	worstDegradation := 0.0
+	for _, dp := range baseline.DataPoints {
+		if dp.Value == 0 {
+			return nil, fmt.Errorf("unable to evaluate regressions: baseline value for size %f is 0", dp.Size)
+		}
+		delta := &RegressionDelta{
+			Size:       dp.Size,
+			Baseline:   dp.Value,
+			Comparison: comparisonValues[dp.Size],
+		}
+		delta.ChangePct = (delta.Comparison - delta.Baseline) / delta.Baseline * 100
+		degradation := delta.ChangePct
+		if metric == Bandwidth {
+			degradation = -degradation
+		}
+		if degradation > thresholdPct {
+			delta.Regression = true
+			report.Failed = true
+			if report.Worst == nil || degradation > worstDegradation {
+				report.Worst = delta
+				worstDegradation = degradation
+			}
+		}
+		report.Deltas = append(report.Deltas, delta)
+	}
+	return report, nil
+}
+
+// NormalizeToBaseline returns the results divided by the baseline, so the baseline is 1 for each size.
+// Data points are aligned by size, not by index, and all the results must have the same sizes as the baseline.
+func NormalizeToBaseline(baseline *Result, others []*Result) ([]*Result, error) {
+	if baseline == nil {
+		return nil, fmt.Errorf("undefined baseline")
+	}
+	baselineValues := make(map[float64]float64)
+	for _, dp := range baseline.DataPoints {
+		baselineValues[dp.Size] = dp.Value
+	}
+
+	var normalized []*Result
+	for i, other := range others {
+		_, err := alignBySize(baseline, other)
+		if err != nil {
+			return nil, fmt.Errorf("unable to normalize result %d: %w", i, err)
+		}
+		n := new(Result)
+		for _, dp := range other.DataPoints {
+			if baselineValues[dp.Size] == 0 {
+				return nil, fmt.Errorf("unable to normalize result %d: baseline value for size %f is 0", i, dp.Size)
+			}
+			n.DataPoints = append(n.DataPoints, &DataPoint{Size: dp.Size, Value: dp.Value / baselineValues[dp.Size]})
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// CompareMany evaluates the regressions of multiple candidates against the same baseline, see EvaluateRegressions.
+// The reports are indexed by the name of the candidates.
+func CompareMany(baseline *Result, candidates map[string]*Result, thresholdPct float64, metric Metric) (map[string]*RegressionReport, error) {
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make(map[string]*RegressionReport)
+	for _, name := range names {
+		report, err := EvaluateRegressions(baseline, candidates[name], thresholdPct, metric)
+		if err != nil {
+			return nil, fmt.Errorf("candidate %s: %w", name, err)
+		}
+		reports[name] = report
+	}
+	return reports, nil
+}
+
+// SignificantDeltas returns a result with only the sizes for which the comparison differs from the baseline by
+// more than minPct percents, in either direction. The value of the data points is the relative change in percent.
+func SignificantDeltas(baseline, comparison *Result, minPct float64) (*Result, error) {
+	if minPct < 0 {
+		return nil, fmt.Errorf("invalid threshold %f, must not be negative", minPct)
+	}
+	comparisonValues, err := alignBySize(baseline, comparison)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute deltas: %w", err)
+	}
+
+	deltas := new(Result)
+	for _, dp := range baseline.DataPoints {
+		if dp.Value == 0 {
+			return nil, fmt.Errorf("unable to compute deltas: baseline value for size %f is 0", dp.Size)
+		}
+		changePct := (comparisonValues[dp.Size] - dp.Value) / dp.Value * 100
+		if math.Abs(changePct) > minPct {
+			deltas.DataPoints = append(deltas.DataPoints, &DataPoint{Size: dp.Size, Value: changePct})
+		}
+	}
+	return deltas, nil
+}
@@ -0,0 +1,453 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpeedupRatio(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseline   *Result
+		comparison *Result
+		metric     Metric
+		expectErr  bool
+		expected   []*DataPoint
+	}{
+		{
+			name:       "latency",
+			baseline:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 6}}},
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 2, Value: 3}, {Size: 1, Value: 2}}},
+			metric:     Latency,
+			expected:   []*DataPoint{{Size: 1, Value: 2}, {Size: 2, Value: 2}},
+		},
+		{
+			name:       "bandwidth",
+			baseline:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}}},
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 2}}},
+			metric:     Bandwidth,
+			expected:   []*DataPoint{{Size: 1, Value: 0.5}},
+		},
+		{
+			name:       "zero latency of the comparison",
+			baseline:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}}},
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 0}}},
+			metric:     Latency,
+			expectErr:  true,
+		},
+		{
+			name:       "zero bandwidth of the baseline",
+			baseline:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 0}}},
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 2}}},
+			metric:     Bandwidth,
+			expectErr:  true,
+		},
+		{
+			name:       "zero latency of the baseline",
+			baseline:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 0}}},
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 2}}},
+			metric:     Latency,
+			expected:   []*DataPoint{{Size: 1, Value: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio, err := SpeedupRatio(tt.baseline, tt.comparison, tt.metric)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("SpeedupRatio() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SpeedupRatio() failed: %s", err)
+			}
+			if !reflect.DeepEqual(ratio.DataPoints, tt.expected) {
+				t.Fatalf("SpeedupRatio() returned unexpected data points")
+			}
+		})
+	}
+}
+
+func TestEvaluateRegressions(t *testing.T) {
+	baseline := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}, {Size: 4, Value: 16}}}
+
+	tests := []struct {
+		name         string
+		baseline     *Result
+		comparison   *Result
+		thresholdPct float64
+		expectErr    bool
+		expected     *RegressionReport
+	}{
+		{
+			name:         "no regression",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 6}, {Size: 4, Value: 16}}},
+			thresholdPct: 10,
+			expected: &RegressionReport{
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 4, ChangePct: 0},
+					{Size: 2, Baseline: 8, Comparison: 6, ChangePct: -25},
+					{Size: 4, Baseline: 16, Comparison: 16, ChangePct: 0},
+				},
+			},
+		},
+		{
+			name:         "worst regression",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 5}, {Size: 2, Value: 12}, {Size: 4, Value: 20}}},
+			thresholdPct: 10,
+			expected: &RegressionReport{
+				Failed: true,
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 5, ChangePct: 25, Regression: true},
+					{Size: 2, Baseline: 8, Comparison: 12, ChangePct: 50, Regression: true},
+					{Size: 4, Baseline: 16, Comparison: 20, ChangePct: 25, Regression: true},
+				},
+				Worst: &RegressionDelta{Size: 2, Baseline: 8, Comparison: 12, ChangePct: 50, Regression: true},
+			},
+		},
+		{
+			name:         "exactly at the threshold",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 5}, {Size: 2, Value: 8}, {Size: 4, Value: 16}}},
+			thresholdPct: 25,
+			expected: &RegressionReport{
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 5, ChangePct: 25},
+					{Size: 2, Baseline: 8, Comparison: 8, ChangePct: 0},
+					{Size: 4, Baseline: 16, Comparison: 16, ChangePct: 0},
+				},
+			},
+		},
+		{
+			name:         "sizes in a different order",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 4, Value: 16}, {Size: 2, Value: 8}, {Size: 1, Value: 6}}},
+			thresholdPct: 0,
+			expected: &RegressionReport{
+				Failed: true,
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 6, ChangePct: 50, Regression: true},
+					{Size: 2, Baseline: 8, Comparison: 8, ChangePct: 0},
+					{Size: 4, Baseline: 16, Comparison: 16, ChangePct: 0},
+				},
+				Worst: &RegressionDelta{Size: 1, Baseline: 4, Comparison: 6, ChangePct: 50, Regression: true},
+			},
+		},
+		{
+			name:         "size missing from the comparison",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}}},
+			thresholdPct: 10,
+			expectErr:    true,
+		},
+		{
+			name:         "size missing from the baseline",
+			baseline:     baseline,
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}, {Size: 4, Value: 16}, {Size: 8, Value: 32}}},
+			thresholdPct: 10,
+			expectErr:    true,
+		},
+		{
+			name:         "zero baseline",
+			baseline:     &Result{DataPoints: []*DataPoint{{Size: 1, Value: 0}}},
+			comparison:   &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}}},
+			thresholdPct: 10,
+			expectErr:    true,
+		},
+		{
+			name:         "negative threshold",
+			baseline:     baseline,
+			comparison:   baseline,
+			thresholdPct: -1,
+			expectErr:    true,
+		},
+		{
+			name:         "undefined comparison",
+			baseline:     baseline,
+			thresholdPct: 10,
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := EvaluateRegressions(tt.baseline, tt.comparison, tt.thresholdPct, Latency)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("EvaluateRegressions() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateRegressions() failed: %s", err)
+			}
+			if !reflect.DeepEqual(report, tt.expected) {
+				t.Fatalf("EvaluateRegressions() returned %+v, expected %+v", report, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateRegressionsMetric(t *testing.T) {
+	baseline := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}}}
+	// Higher value for the first size and lower value for the second one
+	comparison := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 6}, {Size: 2, Value: 4}}}
+
+	tests := []struct {
+		metric    Metric
+		expectErr bool
+		expected  *RegressionReport
+	}{
+		{
+			metric: Latency,
+			expected: &RegressionReport{
+				Failed: true,
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 6, ChangePct: 50, Regression: true},
+					{Size: 2, Baseline: 8, Comparison: 4, ChangePct: -50},
+				},
+				Worst: &RegressionDelta{Size: 1, Baseline: 4, Comparison: 6, ChangePct: 50, Regression: true},
+			},
+		},
+		{
+			metric: Bandwidth,
+			expected: &RegressionReport{
+				Failed: true,
+				Deltas: []*RegressionDelta{
+					{Size: 1, Baseline: 4, Comparison: 6, ChangePct: 50},
+					{Size: 2, Baseline: 8, Comparison: 4, ChangePct: -50, Regression: true},
+				},
+				Worst: &RegressionDelta{Size: 2, Baseline: 8, Comparison: 4, ChangePct: -50, Regression: true},
+			},
+		},
+		{
+			metric:    Metric(42),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric.String(), func(t *testing.T) {
+			report, err := EvaluateRegressions(baseline, comparison, 10, tt.metric)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("EvaluateRegressions() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateRegressions() failed: %s", err)
+			}
+			if !reflect.DeepEqual(report, tt.expected) {
+				t.Fatalf("EvaluateRegressions() returned %+v, expected %+v", report, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetricString(t *testing.T) {
+	tests := []struct {
+		metric   Metric
+		expected string
+	}{
+		{metric: Latency, expected: "latency"},
+		{metric: Bandwidth, expected: "bandwidth"},
+		{metric: Metric(42), expected: "Metric(42)"},
+	}
+
+	for _, tt := range tests {
+		if tt.metric.String() != tt.expected {
+			t.Fatalf("String() returned %q, expected %q", tt.metric.String(), tt.expected)
+		}
+	}
+}
+
+func TestNormalizeToBaseline(t *testing.T) {
+	baseline := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}}}
+
+	tests := []struct {
+		name      string
+		baseline  *Result
+		others    []*Result
+		expectErr bool
+		expected  []*Result
+	}{
+		{
+			name:     "baseline",
+			baseline: baseline,
+			others:   []*Result{baseline},
+			expected: []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 1}, {Size: 2, Value: 1}}}},
+		},
+		{
+			name:     "sizes in a different order",
+			baseline: baseline,
+			others: []*Result{
+				{DataPoints: []*DataPoint{{Size: 2, Value: 4}, {Size: 1, Value: 8}}},
+				{DataPoints: []*DataPoint{{Size: 1, Value: 2}, {Size: 2, Value: 16}}},
+			},
+			expected: []*Result{
+				{DataPoints: []*DataPoint{{Size: 2, Value: 0.5}, {Size: 1, Value: 2}}},
+				{DataPoints: []*DataPoint{{Size: 1, Value: 0.5}, {Size: 2, Value: 2}}},
+			},
+		},
+		{
+			name:     "size missing from a result",
+			baseline: baseline,
+			others: []*Result{
+				baseline,
+				{DataPoints: []*DataPoint{{Size: 1, Value: 2}}},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "zero baseline",
+			baseline:  &Result{DataPoints: []*DataPoint{{Size: 1, Value: 0}}},
+			others:    []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 2}}}},
+			expectErr: true,
+		},
+		{
+			name:      "undefined baseline",
+			others:    []*Result{baseline},
+			expectErr: true,
+		},
+		{
+			name:      "undefined result",
+			baseline:  baseline,
+			others:    []*Result{nil},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := NormalizeToBaseline(tt.baseline, tt.others)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("NormalizeToBaseline() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeToBaseline() failed: %s", err)
+			}
+			if !reflect.DeepEqual(normalized, tt.expected) {
+				t.Fatalf("NormalizeToBaseline() returned unexpected results")
+			}
+		})
+	}
+}
+
+func TestCompareMany(t *testing.T) {
+	baseline := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}}}
+	candidates := map[string]*Result{
+		"faster": {DataPoints: []*DataPoint{{Size: 1, Value: 2}, {Size: 2, Value: 8}}},
+		"slower": {DataPoints: []*DataPoint{{Size: 2, Value: 12}, {Size: 1, Value: 4}}},
+	}
+
+	reports, err := CompareMany(baseline, candidates, 10, Latency)
+	if err != nil {
+		t.Fatalf("CompareMany() failed: %s", err)
+	}
+	worst := &RegressionDelta{Size: 2, Baseline: 8, Comparison: 12, ChangePct: 50, Regression: true}
+	expected := map[string]*RegressionReport{
+		"faster": {
+			Deltas: []*RegressionDelta{
+				{Size: 1, Baseline: 4, Comparison: 2, ChangePct: -50},
+				{Size: 2, Baseline: 8, Comparison: 8, ChangePct: 0},
+			},
+		},
+		"slower": {
+			Failed: true,
+			Deltas: []*RegressionDelta{{Size: 1, Baseline: 4, Comparison: 4, ChangePct: 0}, worst},
+			Worst:  worst,
+		},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Fatalf("CompareMany() returned %+v, expected %+v", reports, expected)
+	}
+
+	candidates["misaligned"] = &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}}}
+	_, err = CompareMany(baseline, candidates, 10, Latency)
+	if err == nil {
+		t.Fatalf("CompareMany() succeeded with a misaligned candidate, expected an error")
+	}
+
+	reports, err = CompareMany(baseline, nil, 10, Latency)
+	if err != nil {
+		t.Fatalf("CompareMany() failed without candidates: %s", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("CompareMany() returned %d reports without candidates", len(reports))
+	}
+}
+
+func TestSignificantDeltas(t *testing.T) {
+	baseline := &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}, {Size: 4, Value: 16}}}
+
+	tests := []struct {
+		name       string
+		comparison *Result
+		minPct     float64
+		expectErr  bool
+		expected   []*DataPoint
+	}{
+		{
+			name:       "changes in both directions",
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 6}, {Size: 2, Value: 8}, {Size: 4, Value: 8}}},
+			minPct:     10,
+			expected:   []*DataPoint{{Size: 1, Value: 50}, {Size: 4, Value: -50}},
+		},
+		{
+			name:       "exactly at the threshold",
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 5}, {Size: 2, Value: 6}, {Size: 4, Value: 24}}},
+			minPct:     25,
+			expected:   []*DataPoint{{Size: 4, Value: 50}},
+		},
+		{
+			name:       "sizes in a different order",
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 4, Value: 16}, {Size: 2, Value: 16}, {Size: 1, Value: 4}}},
+			minPct:     0,
+			expected:   []*DataPoint{{Size: 2, Value: 100}},
+		},
+		{
+			name:       "size missing from the comparison",
+			comparison: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 4}, {Size: 2, Value: 8}}},
+			minPct:     10,
+			expectErr:  true,
+		},
+		{
+			name:       "negative threshold",
+			comparison: baseline,
+			minPct:     -1,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas, err := SignificantDeltas(baseline, tt.comparison, tt.minPct)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("SignificantDeltas() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignificantDeltas() failed: %s", err)
+			}
+			if !reflect.DeepEqual(deltas.DataPoints, tt.expected) {
+				t.Fatalf("SignificantDeltas() returned unexpected data points")
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyTarball checks that the SHA-256 checksum of a downloaded tarball matches the one from the configuration
+func (c *Config) VerifyTarball(path string) error {
+	if c.SHA256 == "" {
+		return fmt.Errorf("no checksum defined for %s", c.Tarball)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("unable to hash %s: %w", path, err)
+	}
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if checksum != strings.ToLower(c.SHA256) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s but got %s", path, c.SHA256, checksum)
+	}
+	return nil
+}
+
+// ResolveURL returns the first reachable URL, trying first the main URL and then the mirrors
+func (c *Config) ResolveURL(ctx context.Context) (string, error) {
+	urls := append([]string{c.URL}, c.Mirrors...)
+	var errs []string
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", url, err))
+			continue
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			errs = append(errs, fmt.Sprintf("%s: %s", url, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			errs = append(errs, fmt.Sprintf("%s: %s", url, resp.Status))
+			continue
+		}
+		return url, nil
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("no URL defined")
+	}
+	return "", fmt.Errorf("no reachable URL: %s", strings.Join(errs, "; "))
+}
+
+// DownloadTarball downloads the tarball from the configuration's URL into a directory and returns the path
+// to the downloaded file. The content is streamed to disk and the download is aborted when the context is canceled.
+func (c *Config) DownloadTarball(ctx context.Context, destDir string) (string, error) {
+	return c.DownloadTarballWithProgress(ctx, destDir, nil)
+}
+
+// progressReader is a reader reporting how much data has been read so far
+type progressReader struct {
+	r          io.Reader
+	downloaded int64
+	total      int64
+	progress   func(downloaded, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.downloaded += int64(n)
+		p.progress(p.downloaded, p.total)
+	}
+	return n, err
+}
+
+// DownloadTarballWithProgress is similar to DownloadTarball but progress, when not nil, is called as data is
+// received with the number of bytes downloaded so far and the total size of the tarball (-1 if unknown)
+func (c *Config) DownloadTarballWithProgress(ctx context.Context, destDir string, progress func(downloaded, total int64)) (string, error) {
+	if c.URL == "" {
+		return "", fmt.Errorf("undefined URL")
+	}
+	filename := c.Tarball
+	if filename == "" {
+		filename = path.Base(c.URL)
+	}
+	tarballPath := filepath.Join(destDir, filename)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to download %s: %w", c.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unable to download %s: %s", c.URL, resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if progress != nil {
+		total := resp.ContentLength
+		if total < 0 {
+			total = -1
+		}
+		progress(0, total)
+		body = &progressReader{r: resp.Body, total: total, progress: progress}
+	}
+
+	f, err := os.Create(tarballPath)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, body)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tarballPath)
+		return "", fmt.Errorf("unable to download %s: %w", c.URL, err)
+	}
+	return tarballPath, nil
+}
+
+// isWithinDir checks that a path is in a directory
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkNoSymlink makes sure that none of the components of a path relative to a directory, including the last
+// one, is an existing symbolic link, so that extracting an entry at that path cannot write outside of the directory
+func checkNoSymlink(dir, rel string) error {
+	path := dir
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		path = filepath.Join(path, component)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symbolic link", path)
+		}
+	}
+	return nil
+}
+
+// Extract extracts a tarball, optionally gzipped, into a directory and returns the path to the top-level
+// directory of the tarball (destDir if the tarball does not have a single top-level directory).
+// Entries that would be extracted outside of destDir or through a symbolic link are rejected, as well as
+// symbolic links that are absolute or contain "..", since those could point outside of destDir, and hard
+// links to files outside of destDir. Only directories, regular files and links are supported.
+func (c *Config) Extract(tarballPath, destDir string) (string, error) {
+	f, err := os.Open(tarballPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	magic, err := br.Peek(2)
+	if err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return "", fmt.Errorf("unable to decompress %s: %w", tarballPath, err)
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+
+	destDir, err = filepath.Abs(destDir)
+	if err != nil {
+		return "", err
+	}
+	topLevelDirs := make(map[string]bool)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to read %s: %w", tarballPath, err)
+		}
+
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			// PAX global headers, e.g., from "git archive", only carry metadata
+			continue
+		}
+
+		target := filepath.Join(destDir, hdr.Name)
+		if filepath.IsAbs(hdr.Name) || !isWithinDir(destDir, target) {
+			return "", fmt.Errorf("invalid entry %s in %s", hdr.Name, tarballPath)
+		}
+		if target == destDir {
+			// Tarballs created from a directory, e.g., with "tar -C dir .", start with a "./" entry
+			continue
+		}
+		rel, _ := filepath.Rel(destDir, target)
+		err = checkNoSymlink(destDir, rel)
+		if err != nil {
+			return "", fmt.Errorf("invalid entry %s in %s: %w", hdr.Name, tarballPath, err)
+		}
+		topLevelDirs[strings.Split(rel, string(filepath.Separator))[0]] = true
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = extractFile(tr, target, os.FileMode(hdr.Mode).Perm())
+		case tar.TypeSymlink:
+			linkTarget := filepath.FromSlash(hdr.Linkname)
+			isUpward := false
+			for _, component := range strings.Split(linkTarget, string(filepath.Separator)) {
+				if component == ".." {
+					isUpward = true
+				}
+			}
+			if filepath.IsAbs(linkTarget) || isUpward {
+				return "", fmt.Errorf("invalid symbolic link %s -> %s in %s", hdr.Name, hdr.Linkname, tarballPath)
+			}
+			err = os.MkdirAll(filepath.Dir(target), 0755)
+			if err == nil {
+				err = os.Symlink(hdr.Linkname, target)
+			}
+		case tar.TypeLink:
+			// The target of hard links is relative to the root of the tarball
+			linkTarget := filepath.Join(destDir, hdr.Linkname)
+			if filepath.IsAbs(hdr.Linkname) || !isWithinDir(destDir, linkTarget) || linkTarget == destDir {
+				return "", fmt.Errorf("invalid hard link %s -> %s in %s", hdr.Name, hdr.Linkname, tarballPath)
+			}
+			linkRel, _ := filepath.Rel(destDir, linkTarget)
+			err = checkNoSymlink(destDir, linkRel)
+			if err != nil {
+				return "", fmt.Errorf("invalid hard link %s -> %s in %s: %w", hdr.Name, hdr.Linkname, tarballPath, err)
+			}
+			err = os.MkdirAll(filepath.Dir(target), 0755)
+			if err == nil {
+				err = os.Link(linkTarget, target)
+			}
+		default:
+			return "", fmt.Errorf("unsupported type %q of entry %s in %s", hdr.Typeflag, hdr.Name, tarballPath)
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to extract %s from %s: %w", hdr.Name, tarballPath, err)
+		}
+	}
+
+	if len(topLevelDirs) == 1 {
+		for dir := range topLevelDirs {
+			topLevelDir := filepath.Join(destDir, dir)
+			info, err := os.Stat(topLevelDir)
+			if err == nil && info.IsDir() {
+				return topLevelDir, nil
+			}
+		}
+	}
+	return destDir, nil
+}
+
+func extractFile(r io.Reader, path string, perm os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,588 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func writeTestTarball(t *testing.T, dir string, entries []tarEntry) string {
+	path := filepath.Join(dir, "test.tar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", path, err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:     e.name,
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+			Mode:     0644,
+			Size:     int64(len(e.content)),
+		}
+		if e.typeflag == tar.TypeDir {
+			hdr.Mode = 0755
+		}
+		if e.typeflag == tar.TypeXGlobalHeader {
+			hdr = &tar.Header{Typeflag: e.typeflag, PAXRecords: map[string]string{"comment": "test"}}
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			t.Fatalf("unable to write header for %s: %s", e.name, err)
+		}
+		_, err = tw.Write([]byte(e.content))
+		if err != nil {
+			t.Fatalf("unable to write %s: %s", e.name, err)
+		}
+	}
+	err = tw.Close()
+	if err != nil {
+		t.Fatalf("unable to close %s: %s", path, err)
+	}
+	return path
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    []tarEntry
+		expectErr  bool
+		topLevel   string
+		extracted  string
+		outsideDir string
+	}{
+		{
+			name: "single top-level directory",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/README", typeflag: tar.TypeReg, content: "osu"},
+			},
+			topLevel:  "osu",
+			extracted: "osu/README",
+		},
+		{
+			name: "leading ./ entry",
+			entries: []tarEntry{
+				{name: "./", typeflag: tar.TypeDir},
+				{name: "./osu/", typeflag: tar.TypeDir},
+				{name: "./osu/README", typeflag: tar.TypeReg, content: "osu"},
+			},
+			topLevel:  "osu",
+			extracted: "osu/README",
+		},
+		{
+			name: "downward symbolic link",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/README", typeflag: tar.TypeReg, content: "osu"},
+				{name: "osu/README.link", typeflag: tar.TypeSymlink, linkname: "README"},
+			},
+			topLevel:  "osu",
+			extracted: "osu/README.link",
+		},
+		{
+			name: "hard link",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/README", typeflag: tar.TypeReg, content: "osu"},
+				{name: "osu/README.hard", typeflag: tar.TypeLink, linkname: "osu/README"},
+			},
+			topLevel:  "osu",
+			extracted: "osu/README.hard",
+		},
+		{
+			name: "PAX global header",
+			entries: []tarEntry{
+				{name: "pax_global_header", typeflag: tar.TypeXGlobalHeader},
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/README", typeflag: tar.TypeReg, content: "osu"},
+			},
+			topLevel:  "osu",
+			extracted: "osu/README",
+		},
+		{
+			name: "hard link outside of the destination",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/passwd", typeflag: tar.TypeLink, linkname: "../../../passwd"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "hard link through a symbolic link",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/d", typeflag: tar.TypeSymlink, linkname: "sub"},
+				{name: "osu/f", typeflag: tar.TypeLink, linkname: "osu/d/f"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unsupported entry type",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/fifo", typeflag: tar.TypeFifo},
+			},
+			expectErr: true,
+		},
+		{
+			name: "parent directory entry",
+			entries: []tarEntry{
+				{name: "../evil.txt", typeflag: tar.TypeReg, content: "evil"},
+			},
+			expectErr:  true,
+			outsideDir: "evil.txt",
+		},
+		{
+			name: "absolute symbolic link",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/l", typeflag: tar.TypeSymlink, linkname: "/tmp"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "upward symbolic link",
+			entries: []tarEntry{
+				{name: "osu/", typeflag: tar.TypeDir},
+				{name: "osu/l", typeflag: tar.TypeSymlink, linkname: "../.."},
+				{name: "osu/l/evil.txt", typeflag: tar.TypeReg, content: "evil"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "chain of symbolic links",
+			entries: []tarEntry{
+				{name: "top/", typeflag: tar.TypeDir},
+				{name: "top/d", typeflag: tar.TypeSymlink, linkname: "."},
+				{name: "top/d/l", typeflag: tar.TypeSymlink, linkname: ".."},
+				{name: "top/d/l/l2", typeflag: tar.TypeSymlink, linkname: ".."},
+				{name: "top/d/l/l2/evil.txt", typeflag: tar.TypeReg, content: "evil"},
+			},
+			expectErr:  true,
+			outsideDir: "evil.txt",
+		},
+		{
+			name: "file written through a symbolic link",
+			entries: []tarEntry{
+				{name: "top/", typeflag: tar.TypeDir},
+				{name: "top/d", typeflag: tar.TypeSymlink, linkname: "sub"},
+				{name: "top/d/evil.txt", typeflag: tar.TypeReg, content: "evil"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "go_benchmark-")
+			if err != nil {
+				t.Fatalf("unable to create temporary directory: %s", err)
+			}
+			defer os.RemoveAll(tempDir)
+			destDir := filepath.Join(tempDir, "a", "b", "dest")
+			err = os.MkdirAll(destDir, 0755)
+			if err != nil {
+				t.Fatalf("unable to create %s: %s", destDir, err)
+			}
+			tarball := writeTestTarball(t, tempDir, tt.entries)
+
+			cfg := new(Config)
+			topLevel, err := cfg.Extract(tarball, destDir)
+			if tt.outsideDir != "" {
+				for dir := destDir; dir != tempDir; dir = filepath.Dir(dir) {
+					_, statErr := os.Stat(filepath.Join(filepath.Dir(dir), tt.outsideDir))
+					if statErr == nil {
+						t.Fatalf("%s was extracted outside of %s", tt.outsideDir, destDir)
+					}
+				}
+			}
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Extract() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Extract() failed: %s", err)
+			}
+			if topLevel != filepath.Join(destDir, tt.topLevel) {
+				t.Fatalf("Extract() returned %s, expected %s", topLevel, filepath.Join(destDir, tt.topLevel))
+			}
+			_, err = os.Stat(filepath.Join(destDir, tt.extracted))
+			if err != nil {
+				t.Fatalf("%s was not extracted: %s", tt.extracted, err)
+			}
+		})
+	}
+}
+
+func TestVerifyTarball(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	tarball := filepath.Join(tempDir, "osu.tar.gz")
+	err = ioutil.WriteFile(tarball, []byte("osu"), 0644)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", tarball, err)
+	}
+	sum := sha256.Sum256([]byte("osu"))
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		path      string
+		checksum  string
+		expectErr bool
+	}{
+		{
+			name:     "matching checksum",
+			path:     tarball,
+			checksum: checksum,
+		},
+		{
+			name:     "upper case checksum",
+			path:     tarball,
+			checksum: strings.ToUpper(checksum),
+		},
+		{
+			name:      "checksum mismatch",
+			path:      tarball,
+			checksum:  strings.Repeat("0", 64),
+			expectErr: true,
+		},
+		{
+			name:      "no checksum",
+			path:      tarball,
+			expectErr: true,
+		},
+		{
+			name:      "missing tarball",
+			path:      filepath.Join(tempDir, "missing.tar.gz"),
+			checksum:  checksum,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Tarball: "osu.tar.gz", SHA256: tt.checksum}
+			err := cfg.VerifyTarball(tt.path)
+			if tt.expectErr && err == nil {
+				t.Fatalf("VerifyTarball() succeeded, expected an error")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("VerifyTarball() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	// A closed server gives an unreachable URL
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := closedServer.URL + "/osu.tar.gz"
+	closedServer.Close()
+
+	tests := []struct {
+		name              string
+		url               string
+		mirrors           []string
+		expectErr         bool
+		expectedURL       string
+		expectedRequested []string
+	}{
+		{
+			name:              "main URL",
+			url:               server.URL + "/main.tar.gz",
+			mirrors:           []string{server.URL + "/mirror.tar.gz"},
+			expectedURL:       server.URL + "/main.tar.gz",
+			expectedRequested: []string{"HEAD /main.tar.gz"},
+		},
+		{
+			name:              "mirrors in order",
+			url:               server.URL + "/missing-main.tar.gz",
+			mirrors:           []string{unreachableURL, server.URL + "/missing-mirror.tar.gz", server.URL + "/mirror1.tar.gz", server.URL + "/mirror2.tar.gz"},
+			expectedURL:       server.URL + "/mirror1.tar.gz",
+			expectedRequested: []string{"HEAD /missing-main.tar.gz", "HEAD /missing-mirror.tar.gz", "HEAD /mirror1.tar.gz"},
+		},
+		{
+			name:              "mirrors only",
+			mirrors:           []string{server.URL + "/mirror.tar.gz"},
+			expectedURL:       server.URL + "/mirror.tar.gz",
+			expectedRequested: []string{"HEAD /mirror.tar.gz"},
+		},
+		{
+			name:              "no reachable URL",
+			url:               server.URL + "/missing-main.tar.gz",
+			mirrors:           []string{unreachableURL},
+			expectErr:         true,
+			expectedRequested: []string{"HEAD /missing-main.tar.gz"},
+		},
+		{
+			name:      "no URL",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			requested = nil
+			mu.Unlock()
+
+			cfg := &Config{URL: tt.url, Mirrors: tt.mirrors}
+			url, err := cfg.ResolveURL(context.Background())
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ResolveURL() succeeded, expected an error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ResolveURL() failed: %s", err)
+				}
+				if url != tt.expectedURL {
+					t.Fatalf("ResolveURL() returned %s, expected %s", url, tt.expectedURL)
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(requested, tt.expectedRequested) {
+				t.Fatalf("ResolveURL() sent %v, expected %v", requested, tt.expectedRequested)
+			}
+		})
+	}
+}
+
+func TestResolveURLCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg := &Config{URL: server.URL + "/main.tar.gz", Mirrors: []string{server.URL + "/mirror.tar.gz"}}
+	_, err := cfg.ResolveURL(ctx)
+	if err != context.Canceled {
+		t.Fatalf("ResolveURL() returned %v, expected %v", err, context.Canceled)
+	}
+}
+
+func TestDownloadTarball(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/osu.tar.gz" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("osu"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		cfg              *Config
+		expectErr        bool
+		expectedFilename string
+	}{
+		{
+			name:             "file name from the URL",
+			cfg:              &Config{URL: server.URL + "/osu.tar.gz"},
+			expectedFilename: "osu.tar.gz",
+		},
+		{
+			name:             "file name from the configuration",
+			cfg:              &Config{URL: server.URL + "/osu.tar.gz", Tarball: "osu-5.8.tar.gz"},
+			expectedFilename: "osu-5.8.tar.gz",
+		},
+		{
+			name:             "error status",
+			cfg:              &Config{URL: server.URL + "/forbidden.tar.gz"},
+			expectErr:        true,
+			expectedFilename: "forbidden.tar.gz",
+		},
+		{
+			name:      "no URL",
+			cfg:       &Config{},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "go_benchmark-")
+			if err != nil {
+				t.Fatalf("unable to create temporary directory: %s", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			path, err := tt.cfg.DownloadTarball(context.Background(), tempDir)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("DownloadTarball() succeeded, expected an error")
+				}
+				if tt.expectedFilename != "" {
+					_, err = os.Stat(filepath.Join(tempDir, tt.expectedFilename))
+					if !os.IsNotExist(err) {
+						t.Fatalf("DownloadTarball() left %s behind", tt.expectedFilename)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadTarball() failed: %s", err)
+			}
+			if path != filepath.Join(tempDir, tt.expectedFilename) {
+				t.Fatalf("DownloadTarball() returned %s, expected %s", path, filepath.Join(tempDir, tt.expectedFilename))
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil || string(content) != "osu" {
+				t.Fatalf("DownloadTarball() wrote %q (%v), expected %q", content, err, "osu")
+			}
+		})
+	}
+}
+
+func TestDownloadTarballCanceled(t *testing.T) {
+	sent := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		close(sent)
+		// The rest of the content never comes
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-sent
+		// Cancel once the partial content is on disk
+		for i := 0; i < 500; i++ {
+			info, err := os.Stat(filepath.Join(tempDir, "osu.tar.gz"))
+			if err == nil && info.Size() > 0 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+	}()
+	cfg := &Config{URL: server.URL + "/osu.tar.gz"}
+	_, err = cfg.DownloadTarball(ctx, tempDir)
+	if err == nil {
+		t.Fatalf("DownloadTarball() succeeded, expected an error")
+	}
+	_, err = os.Stat(filepath.Join(tempDir, "osu.tar.gz"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("DownloadTarball() did not remove the partial file")
+	}
+}
+
+func TestDownloadTarballWithProgress(t *testing.T) {
+	content := strings.Repeat("osu", 1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked.tar.gz" {
+			// Flushing before the end of the content prevents the server from setting Content-Length
+			w.Write([]byte(content[:10]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(content[10:]))
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedTotal int64
+	}{
+		{
+			name:          "known size",
+			url:           server.URL + "/osu.tar.gz",
+			expectedTotal: int64(len(content)),
+		},
+		{
+			name:          "unknown size",
+			url:           server.URL + "/chunked.tar.gz",
+			expectedTotal: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "go_benchmark-")
+			if err != nil {
+				t.Fatalf("unable to create temporary directory: %s", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			var downloads, totals []int64
+			cfg := &Config{URL: tt.url}
+			_, err = cfg.DownloadTarballWithProgress(context.Background(), tempDir, func(downloaded, total int64) {
+				downloads = append(downloads, downloaded)
+				totals = append(totals, total)
+			})
+			if err != nil {
+				t.Fatalf("DownloadTarballWithProgress() failed: %s", err)
+			}
+			if len(downloads) < 2 || downloads[0] != 0 || downloads[len(downloads)-1] != int64(len(content)) {
+				t.Fatalf("progress was reported for %v, expected 0 up to %d", downloads, len(content))
+			}
+			for i, total := range totals {
+				if total != tt.expectedTotal {
+					t.Fatalf("progress reported a total of %d, expected %d", total, tt.expectedTotal)
+				}
+				if i > 0 && downloads[i] < downloads[i-1] {
+					t.Fatalf("progress went backward: %v", downloads)
+				}
+			}
+		})
+	}
+}
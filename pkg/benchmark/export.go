@@ -0,0 +1,236 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExportOptions gathers the options of the text exporters
+type ExportOptions struct {
+	// Precision is the number of decimals of the values, nil to use the default precision of the exporter;
+	// a negative precision uses the smallest number of digits necessary to represent the values exactly
+	Precision *int
+
+	// NonFinite is the content of the cells with a NaN or infinite value, e.g., "NaN" or "-"; the cells are
+	// left empty by default
+	NonFinite string
+
+	// ExtraColumns are the keys of the extra values to export, e.g., ExtraMin and ExtraMax, in additional
+	// columns after the column of each result, labeled "<label> (<key>)". Cells are empty for the data points
+	// without the extra value.
+	ExtraColumns []string
+
+	// OnNonFinite, when set, is called once per export with the number of NaN or infinite values that were
+	// replaced by NonFinite, if any
+	OnNonFinite func(count int)
+}
+
+// defaultPrecision is the precision used by the exporters when it is not set in the options
+const defaultPrecision = -1
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// valueFormatter returns the function formatting values based on the options, using exporterPrecision
+// when opts is nil or does not set the precision
+func valueFormatter(opts *ExportOptions, exporterPrecision int) func(float64) string {
+	precision := exporterPrecision
+	if opts != nil && opts.Precision != nil {
+		precision = *opts.Precision
+	}
+	return func(value float64) string {
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+}
+
+// nonFinitePlaceholder returns the content of the cells with a non-finite value based on the options,
+// which can be nil
+func nonFinitePlaceholder(opts *ExportOptions) string {
+	if opts == nil {
+		return ""
+	}
+	return opts.NonFinite
+}
+
+// reportNonFinite notifies the caller of the number of non-finite values of an export through the options,
+// which can be nil
+func reportNonFinite(opts *ExportOptions, count int) {
+	if opts != nil && opts.OnNonFinite != nil && count > 0 {
+		opts.OnNonFinite(count)
+	}
+}
+
+// exportTable returns the table to export, with the extra columns requested in the options, which can be nil
+func exportTable(spreadsheetData *SpreadsheetData, opts *ExportOptions) (*dataTable, error) {
+	t, err := newDataTable(spreadsheetData)
+	if err != nil || opts == nil || len(opts.ExtraColumns) == 0 {
+		return t, err
+	}
+
+	expanded := &SpreadsheetData{Data: new(Results)}
+	for i, r := range spreadsheetData.Data.Result {
+		expanded.Labels = append(expanded.Labels, t.labels[i])
+		expanded.Data.Result = append(expanded.Data.Result, r)
+		for _, key := range opts.ExtraColumns {
+			var extra *Result
+			if r != nil {
+				extra = new(Result)
+				for _, dp := range r.DataPoints {
+					value, ok := dp.Extra[key]
+					if ok {
+						extra.DataPoints = append(extra.DataPoints, &DataPoint{Size: dp.Size, Value: value})
+					}
+				}
+			}
+			expanded.Labels = append(expanded.Labels, strings.TrimSpace(t.labels[i]+" ("+key+")"))
+			expanded.Data.Result = append(expanded.Data.Result, extra)
+		}
+	}
+	return newDataTable(expanded)
+}
+
+// rows returns the rows of the table as strings, without the header, and the number of NaN or infinite values.
+// Cells for sizes a result does not have are empty and cells with a NaN or infinite value are set to nonFinite.
+func (t *dataTable) rows(formatValue func(float64) string, nonFinite string) ([][]string, int) {
+	var rows [][]string
+	numNonFinite := 0
+	for rowID, size := range t.sizes {
+		row := []string{formatFloat(size)}
+		for _, cell := range t.cells[rowID] {
+			if cell == nil {
+				row = append(row, "")
+				continue
+			}
+			if math.IsNaN(*cell) || math.IsInf(*cell, 0) {
+				numNonFinite++
+				row = append(row, nonFinite)
+				continue
+			}
+			row = append(row, formatValue(*cell))
+		}
+		rows = append(rows, row)
+	}
+	return rows, numNonFinite
+}
+
+// ExportCSV writes the data as CSV, using the same layout as the spreadsheets: the first column
+// is the message size and there is one column per result, followed by the extra columns requested in the options.
+// Cells for sizes a result does not have are left empty.
+// opts can be nil to use the default options.
+func ExportCSV(w io.Writer, spreadsheetData *SpreadsheetData, opts *ExportOptions) error {
+	t, err := exportTable(spreadsheetData, opts)
+	if err != nil {
+		return fmt.Errorf("unable to export CSV: %w", err)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	header := append([]string{""}, t.labels...)
+	err = csvWriter.Write(header)
+	if err != nil {
+		return err
+	}
+	records, numNonFinite := t.rows(valueFormatter(opts, defaultPrecision), nonFinitePlaceholder(opts))
+	reportNonFinite(opts, numNonFinite)
+	for _, record := range records {
+		err = csvWriter.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// defaultMarkdownPrecision is the default precision of Markdown tables, which are meant to be read by humans
+const defaultMarkdownPrecision = 2
+
+// ExportMarkdown writes the data as a Markdown table with a size column and one right-aligned column per result.
+// Pipes in labels are escaped and new lines are replaced by spaces so that they do not break the table.
+// opts can be nil to use the default options; values have 2 decimals unless the options set the precision.
+func ExportMarkdown(w io.Writer, spreadsheetData *SpreadsheetData, opts *ExportOptions) error {
+	t, err := exportTable(spreadsheetData, opts)
+	if err != nil {
+		return fmt.Errorf("unable to export Markdown: %w", err)
+	}
+
+	formatValue := valueFormatter(opts, defaultMarkdownPrecision)
+	valueRows, numNonFinite := t.rows(formatValue, nonFinitePlaceholder(opts))
+	reportNonFinite(opts, numNonFinite)
+	rows := append([][]string{append([]string{"Size"}, t.labels...)}, valueRows...)
+
+	cellReplacer := strings.NewReplacer("|", "\\|", "\r\n", " ", "\r", " ", "\n", " ")
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for col, cell := range row {
+			row[col] = cellReplacer.Replace(cell)
+			// Widths are in runes, like the padding of fmt
+			width := utf8.RuneCountInString(row[col])
+			if width > widths[col] {
+				widths[col] = width
+			}
+		}
+	}
+	separator := make([]string, len(widths))
+	for col, width := range widths {
+		if width < 3 {
+			widths[col] = 3
+		}
+		separator[col] = strings.Repeat("-", widths[col]-1) + ":"
+	}
+
+	for rowID, row := range rows {
+		line := "|"
+		for col, cell := range row {
+			line += fmt.Sprintf(" %*s |", widths[col], cell)
+		}
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return err
+		}
+		if rowID == 0 {
+			_, err = fmt.Fprintln(w, "| "+strings.Join(separator, " | ")+" |")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExportTSV writes the data as tab-separated values, e.g., for gnuplot: a header line with the labels and then
+// one line per size. Values are not quoted, tabs and new lines in labels are replaced by spaces.
+// opts can be nil to use the default options.
+func ExportTSV(w io.Writer, spreadsheetData *SpreadsheetData, opts *ExportOptions) error {
+	t, err := exportTable(spreadsheetData, opts)
+	if err != nil {
+		return fmt.Errorf("unable to export TSV: %w", err)
+	}
+
+	labelReplacer := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+	header := []string{""}
+	for _, label := range t.labels {
+		header = append(header, labelReplacer.Replace(label))
+	}
+	valueLines, numNonFinite := t.rows(valueFormatter(opts, defaultPrecision), nonFinitePlaceholder(opts))
+	reportNonFinite(opts, numNonFinite)
+	lines := append([][]string{header}, valueLines...)
+	for _, line := range lines {
+		_, err = fmt.Fprintln(w, strings.Join(line, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
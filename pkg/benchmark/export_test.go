@@ -0,0 +1,324 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExportCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      *SpreadsheetData
+		expectErr bool
+		expected  string
+	}{
+		{
+			name: "missing data points",
+			data: &SpreadsheetData{
+				Labels: []string{"a", "b, c"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 1, Value: 1.5}, {Size: 2, Value: 2.5}}},
+					{DataPoints: []*DataPoint{{Size: 2, Value: 3}}},
+				}},
+			},
+			expected: ",a,\"b, c\"\n1,1.5,\n2,2.5,3\n",
+		},
+		{
+			name: "undefined result",
+			data: &SpreadsheetData{
+				Labels: []string{"a", "b"},
+				Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 1}}}, nil}},
+			},
+			expected: ",a,b\n1,1,\n",
+		},
+		{
+			name: "label with quotes",
+			data: &SpreadsheetData{
+				Labels: []string{`say "hi"`},
+				Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 1024, Value: 0.25}}}}},
+			},
+			expected: ",\"say \"\"hi\"\"\"\n1024,0.25\n",
+		},
+		{
+			name:      "undefined data",
+			data:      &SpreadsheetData{Labels: []string{"a"}},
+			expectErr: true,
+		},
+		{
+			name: "undefined data point",
+			data: &SpreadsheetData{
+				Labels: []string{"a"},
+				Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 1}, nil}}}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := ExportCSV(&buf, tt.data, nil)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ExportCSV() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportCSV() failed: %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Fatalf("ExportCSV() wrote %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportNonFinite(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     *SpreadsheetData
+		opts     *ExportOptions
+		expected string
+	}{
+		{
+			name: "non-finite values",
+			data: &SpreadsheetData{
+				Labels: []string{"a"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 1, Value: math.NaN()}, {Size: 2, Value: math.Inf(1)}, {Size: 4, Value: 4}}},
+				}},
+			},
+			expected: ",a\n1,\n2,\n4,4\n",
+		},
+		{
+			name: "non-finite values with a placeholder",
+			data: &SpreadsheetData{
+				Labels: []string{"a"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 1, Value: math.NaN()}, {Size: 2, Value: math.Inf(-1)}, {Size: 4, Value: 4}}},
+				}},
+			},
+			opts:     &ExportOptions{NonFinite: "NaN"},
+			expected: ",a\n1,NaN\n2,NaN\n4,4\n",
+		},
+		{
+			name: "placeholder without precision",
+			data: &SpreadsheetData{
+				Labels: []string{"a"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 1, Value: math.NaN()}, {Size: 2, Value: 1.47}}},
+				}},
+			},
+			opts:     &ExportOptions{NonFinite: "-"},
+			expected: ",a\n1,-\n2,1.47\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := ExportCSV(&buf, tt.data, tt.opts)
+			if err != nil {
+				t.Fatalf("ExportCSV() failed: %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Fatalf("ExportCSV() wrote %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportNonFiniteReport(t *testing.T) {
+	data := &SpreadsheetData{
+		Labels: []string{"a", "b"},
+		Data: &Results{Result: []*Result{
+			{DataPoints: []*DataPoint{{Size: 1, Value: math.NaN()}, {Size: 2, Value: math.Inf(1)}}},
+			{DataPoints: []*DataPoint{{Size: 1, Value: 1}, {Size: 2, Value: 2}}},
+		}},
+	}
+	finiteData := &SpreadsheetData{
+		Labels: []string{"a"},
+		Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 1}}}}},
+	}
+
+	exporters := []struct {
+		name   string
+		export func(w io.Writer, sd *SpreadsheetData, opts *ExportOptions) error
+	}{
+		{name: "CSV", export: ExportCSV},
+		{name: "Markdown", export: ExportMarkdown},
+		{name: "TSV", export: ExportTSV},
+	}
+	for _, exporter := range exporters {
+		t.Run(exporter.name, func(t *testing.T) {
+			var calls []int
+			opts := &ExportOptions{OnNonFinite: func(count int) { calls = append(calls, count) }}
+			var buf bytes.Buffer
+			err := exporter.export(&buf, data, opts)
+			if err != nil {
+				t.Fatalf("export failed: %s", err)
+			}
+			if len(calls) != 1 || calls[0] != 2 {
+				t.Fatalf("OnNonFinite was called with %v, expected a single call with 2", calls)
+			}
+
+			calls = nil
+			err = exporter.export(&buf, finiteData, opts)
+			if err != nil {
+				t.Fatalf("export failed: %s", err)
+			}
+			if len(calls) != 0 {
+				t.Fatalf("OnNonFinite was called with %v without non-finite values", calls)
+			}
+		})
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		expected string
+	}{
+		{
+			name:   "plain label",
+			labels: []string{"run"},
+			expected: "| Size |  run |\n" +
+				"| ---: | ---: |\n" +
+				"|    1 | 1.50 |\n",
+		},
+		{
+			name:   "label with a pipe and a new line",
+			labels: []string{"a|b\nc"},
+			expected: "| Size | a\\|b c |\n" +
+				"| ---: | -----: |\n" +
+				"|    1 |   1.50 |\n",
+		},
+		{
+			name:   "non-ASCII label",
+			labels: []string{"débit µs"},
+			expected: "| Size | débit µs |\n" +
+				"| ---: | -------: |\n" +
+				"|    1 |     1.50 |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &SpreadsheetData{
+				Labels: tt.labels,
+				Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 1, Value: 1.5}}}}},
+			}
+			var buf bytes.Buffer
+			err := ExportMarkdown(&buf, data, nil)
+			if err != nil {
+				t.Fatalf("ExportMarkdown() failed: %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Fatalf("ExportMarkdown() wrote %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportSizeOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      *SpreadsheetData
+		expectErr bool
+		expected  string
+	}{
+		{
+			name: "unsorted sizes",
+			data: &SpreadsheetData{
+				Labels: []string{"a", "b"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 2, Value: 20}, {Size: 1, Value: 10}}},
+					{DataPoints: []*DataPoint{{Size: 4, Value: 40}, {Size: 1, Value: 11}}},
+				}},
+			},
+			expected: ",a,b\n1,10,11\n2,20,\n4,,40\n",
+		},
+		{
+			name: "duplicate sizes",
+			data: &SpreadsheetData{
+				Labels: []string{"a"},
+				Data: &Results{Result: []*Result{
+					{DataPoints: []*DataPoint{{Size: 2, Value: 20}, {Size: 1, Value: 10}, {Size: 2, Value: 21}}},
+				}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := ExportCSV(&buf, tt.data, nil)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ExportCSV() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportCSV() failed: %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Fatalf("ExportCSV() wrote %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportPrecision(t *testing.T) {
+	data := &SpreadsheetData{
+		Labels: []string{"a"},
+		Data:   &Results{Result: []*Result{{DataPoints: []*DataPoint{{Size: 8, Value: 1.23456}}}}},
+	}
+	precision := func(p int) *int { return &p }
+
+	tests := []struct {
+		name      string
+		export    func(w io.Writer, sd *SpreadsheetData, opts *ExportOptions) error
+		separator string
+		opts      *ExportOptions
+		expected  string
+	}{
+		{name: "CSV default", export: ExportCSV, separator: ",", expected: "1.23456"},
+		{name: "CSV without decimals", export: ExportCSV, separator: ",", opts: &ExportOptions{Precision: precision(0)}, expected: "1"},
+		{name: "CSV with 3 decimals", export: ExportCSV, separator: ",", opts: &ExportOptions{Precision: precision(3)}, expected: "1.235"},
+		{name: "Markdown default", export: ExportMarkdown, separator: "|", expected: "1.23"},
+		{name: "Markdown default with options", export: ExportMarkdown, separator: "|", opts: &ExportOptions{NonFinite: "-"}, expected: "1.23"},
+		{name: "Markdown without decimals", export: ExportMarkdown, separator: "|", opts: &ExportOptions{Precision: precision(0)}, expected: "1"},
+		{name: "Markdown with exact values", export: ExportMarkdown, separator: "|", opts: &ExportOptions{Precision: precision(-1)}, expected: "1.23456"},
+		{name: "TSV default", export: ExportTSV, separator: "\t", expected: "1.23456"},
+		{name: "TSV with 1 decimal", export: ExportTSV, separator: "\t", opts: &ExportOptions{Precision: precision(1)}, expected: "1.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.export(&buf, data, tt.opts)
+			if err != nil {
+				t.Fatalf("export failed: %s", err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			cells := strings.Split(strings.Trim(lines[len(lines)-1], "| "), tt.separator)
+			value := strings.TrimSpace(cells[len(cells)-1])
+			if value != tt.expected {
+				t.Fatalf("exported %q, expected %q", value, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// gnuplotString quotes a string so it can be used in a gnuplot script
+func gnuplotString(str string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(str) + `"`
+}
+
+// gnuplotYLabel returns the label of the Y axis based on the type of benchmark and the units of the values
+// in the metadata, e.g., "Latency (us)" for the metadata returned by ParseOSUOutput, or "Value" by default
+func gnuplotYLabel(spreadsheetMetadata *SpreadsheetMetadata) string {
+	label := "Value"
+	units := ""
+	if spreadsheetMetadata != nil {
+		for _, line := range spreadsheetMetadata.Content {
+			switch {
+			case strings.HasPrefix(line, metadataBenchmarkType):
+				switch strings.TrimPrefix(line, metadataBenchmarkType) {
+				case LatencyBenchmarkType:
+					label = "Latency"
+				case BandwidthBenchmarkType:
+					label = "Bandwidth"
+				case BiBandwidthBenchmarkType:
+					label = "Bi-directional bandwidth"
+				}
+			case strings.HasPrefix(line, metadataUnits):
+				units = strings.TrimPrefix(line, metadataUnits)
+			}
+		}
+	}
+	if units != "" {
+		label += " (" + units + ")"
+	}
+	return label
+}
+
+// ExportGnuplotScript writes a gnuplot script plotting each column of a data file created with ExportTSV
+// against the size column, optionally with logarithmic axes. The label of the Y axis is derived from the
+// type of benchmark and the units in the metadata, which can be nil.
+func ExportGnuplotScript(w io.Writer, dataFile string, spreadsheetMetadata *SpreadsheetMetadata, spreadsheetData *SpreadsheetData, logX, logY bool) error {
+	t, err := newDataTable(spreadsheetData)
+	if err != nil {
+		return fmt.Errorf("unable to export gnuplot script: %w", err)
+	}
+
+	var script strings.Builder
+	script.WriteString("set datafile separator \"\\t\"\n")
+	script.WriteString("set key autotitle columnhead\n")
+	script.WriteString("set xlabel \"Message size (bytes)\"\n")
+	script.WriteString("set ylabel " + gnuplotString(gnuplotYLabel(spreadsheetMetadata)) + "\n")
+	if logX {
+		script.WriteString("set logscale x 2\n")
+	}
+	if logY {
+		script.WriteString("set logscale y\n")
+	}
+
+	var plots []string
+	for col := range t.labels {
+		file := `""`
+		if col == 0 {
+			file = gnuplotString(dataFile)
+		}
+		plots = append(plots, fmt.Sprintf("%s using 1:%d with linespoints", file, col+2))
+	}
+	script.WriteString("plot " + strings.Join(plots, ", \\\n     ") + "\n")
+
+	_, err = io.WriteString(w, script.String())
+	return err
+}
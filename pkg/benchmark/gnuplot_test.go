@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportGnuplotScript(t *testing.T) {
+	data := &SpreadsheetData{
+		Labels: []string{"a", "b"},
+		Data: &Results{Result: []*Result{
+			{DataPoints: []*DataPoint{{Size: 1, Value: 1.5}}},
+			{DataPoints: []*DataPoint{{Size: 1, Value: 2.5}}},
+		}},
+	}
+	_, latencyMetadata, err := ParseOSUOutput(strings.NewReader(osuLatencyOutput))
+	if err != nil {
+		t.Fatalf("ParseOSUOutput() failed: %s", err)
+	}
+	_, bandwidthMetadata, err := ParseOSUOutput(strings.NewReader(osuBiBandwidthOutput))
+	if err != nil {
+		t.Fatalf("ParseOSUOutput() failed: %s", err)
+	}
+
+	tests := []struct {
+		name           string
+		metadata       *SpreadsheetMetadata
+		expectedYLabel string
+	}{
+		{name: "no metadata", expectedYLabel: "Value"},
+		{name: "metadata without units", metadata: &SpreadsheetMetadata{Content: []string{"Run 1"}}, expectedYLabel: "Value"},
+		{name: "latency", metadata: latencyMetadata, expectedYLabel: "Latency (us)"},
+		{name: "bi-directional bandwidth", metadata: bandwidthMetadata, expectedYLabel: "Bi-directional bandwidth (MB/s)"},
+		{name: "units only", metadata: &SpreadsheetMetadata{Content: []string{metadataUnits + "ms"}}, expectedYLabel: "Value (ms)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := ExportGnuplotScript(&buf, "data \"1\".tsv", tt.metadata, data, true, false)
+			if err != nil {
+				t.Fatalf("ExportGnuplotScript() failed: %s", err)
+			}
+			expected := "set datafile separator \"\\t\"\n" +
+				"set key autotitle columnhead\n" +
+				"set xlabel \"Message size (bytes)\"\n" +
+				"set ylabel \"" + tt.expectedYLabel + "\"\n" +
+				"set logscale x 2\n" +
+				"plot \"data \\\"1\\\".tsv\" using 1:2 with linespoints, \\\n" +
+				"     \"\" using 1:3 with linespoints\n"
+			if buf.String() != expected {
+				t.Fatalf("ExportGnuplotScript() wrote %q, expected %q", buf.String(), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Benchmark results</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #cccccc; padding: 4px 8px; text-align: right; }
+</style>
+</head>
+<body>
+<h1>Benchmark results</h1>
+{{- if .Metadata}}
+{{- if .Metadata.Timestamp}}
+<p>Generated on {{.Metadata.Timestamp}}</p>
+{{- end}}
+{{- if .Metadata.Content}}
+<ul>
+{{- range .Metadata.Content}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- if .Chart}}
+{{.Chart}}
+{{- else if .ChartError}}
+<p>No chart: {{.ChartError}}</p>
+{{- end}}
+<table>
+<tr><th>Size</th>{{range .Labels}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// ExportHTML writes a self-contained HTML report with the metadata, a chart and a table of the data.
+// The sizes are on a logarithmic axis unless some of them are not positive. When the data cannot be
+// plotted, e.g., when there is no finite value, the report only includes the table, where NaN and infinite
+// values are left empty.
+func ExportHTML(w io.Writer, spreadsheetMetadata *SpreadsheetMetadata, spreadsheetData *SpreadsheetData) error {
+	t, err := newDataTable(spreadsheetData)
+	if err != nil {
+		return fmt.Errorf("unable to export HTML: %w", err)
+	}
+
+	logX := true
+	for _, size := range t.sizes {
+		if size <= 0 {
+			logX = false
+			break
+		}
+	}
+	var chart bytes.Buffer
+	opts := PlotOptions{
+		XLabel: "Message size (bytes)",
+		LogX:   logX,
+	}
+	chartErr := PlotResultsSVG(&chart, spreadsheetData, opts)
+	if chartErr != nil {
+		chart.Reset()
+	}
+
+	rows, _ := t.rows(formatFloat, "")
+	data := struct {
+		Metadata   *SpreadsheetMetadata
+		Chart      template.HTML
+		ChartError error
+		Labels     []string
+		Rows       [][]string
+	}{
+		Metadata: spreadsheetMetadata,
+		// The chart is generated by PlotResultsSVG, which escapes all the text it includes
+		Chart:      template.HTML(chart.String()),
+		ChartError: chartErr,
+		Labels:     t.labels,
+		Rows:       rows,
+	}
+	return htmlReportTemplate.Execute(w, data)
+}
@@ -0,0 +1,59 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExportHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		dataPoints  []*DataPoint
+		expectChart bool
+	}{
+		{
+			name:        "positive sizes",
+			dataPoints:  []*DataPoint{{Size: 1, Value: 1.5}, {Size: 2, Value: 2.5}},
+			expectChart: true,
+		},
+		{
+			name:        "zero size",
+			dataPoints:  []*DataPoint{{Size: 0, Value: 1.5}, {Size: 2, Value: 2.5}},
+			expectChart: true,
+		},
+		{
+			name:        "no finite value",
+			dataPoints:  []*DataPoint{{Size: 1, Value: math.NaN()}},
+			expectChart: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &SpreadsheetData{
+				Labels: []string{"run"},
+				Data:   &Results{Result: []*Result{{DataPoints: tt.dataPoints}}},
+			}
+			var buf bytes.Buffer
+			err := ExportHTML(&buf, nil, data)
+			if err != nil {
+				t.Fatalf("ExportHTML() failed: %s", err)
+			}
+			report := buf.String()
+			if strings.Contains(report, "<svg") != tt.expectChart {
+				t.Fatalf("ExportHTML() wrote a report with a chart: %t, expected %t", !tt.expectChart, tt.expectChart)
+			}
+			if !strings.Contains(report, "<th>run</th>") || strings.Count(report, "<tr>") != len(tt.dataPoints)+1 {
+				t.Fatalf("ExportHTML() did not write the table")
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gvallee/go_software_build/pkg/app"
+)
+
+// Save writes the details about the installation in a JSON file so it can be loaded later on with LoadInstall
+func (i *Install) Save(path string) error {
+	content, err := json.MarshalIndent(i.SubBenchmarks, "", "\t")
+	if err != nil {
+		return fmt.Errorf("unable to encode installation details: %w", err)
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// LoadInstall loads the details about an installation from a file created with Install.Save
+func LoadInstall(path string) (*Install, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	i := new(Install)
+	err = json.Unmarshal(content, &i.SubBenchmarks)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode installation details from %s: %w", path, err)
+	}
+	return i, nil
+}
+
+// Find looks up a sub-benchmark by name, based on either the name of the application or the name of its binary
+func (i *Install) Find(name string) (*app.Info, error) {
+	for idx := range i.SubBenchmarks {
+		subBenchmark := &i.SubBenchmarks[idx]
+		if subBenchmark.Name == name || subBenchmark.BinName == name {
+			return subBenchmark, nil
+		}
+	}
+	return nil, fmt.Errorf("sub-benchmark %s not found", name)
+}
+
+// ScanInstall looks for the OSU benchmarks' executables, i.e., executable files which name starts with
+// "osu_", in a directory and its sub-directories. When multiple executables have the same name, the first
+// one in lexical order is used.
+func ScanInstall(rootDir string) (*Install, error) {
+	i := new(Install)
+	found := make(map[string]bool)
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// libtool keeps the actual binaries in .libs and wrapper scripts in the parent directory
+			if info.Name() == ".libs" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if !strings.HasPrefix(name, "osu_") || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 || found[name] {
+			return nil
+		}
+		found[name] = true
+		i.SubBenchmarks = append(i.SubBenchmarks, app.Info{
+			Name:    name,
+			BinName: name,
+			BinPath: path,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan %s: %w", rootDir, err)
+	}
+	return i, nil
+}
+
+const (
+	// PointToPointCategory is the category of the point-to-point benchmarks, e.g., osu_latency
+	PointToPointCategory = "pt2pt"
+
+	// CollectiveCategory is the category of the collective benchmarks, e.g., osu_allreduce
+	CollectiveCategory = "collective"
+
+	// OneSidedCategory is the category of the one-sided benchmarks, e.g., osu_put_latency
+	OneSidedCategory = "one-sided"
+
+	// StartupCategory is the category of the startup benchmarks, e.g., osu_init
+	StartupCategory = "startup"
+)
+
+// categories are the known categories of benchmarks, which are also the names of the directories OSU uses for them
+var categories = []string{PointToPointCategory, CollectiveCategory, OneSidedCategory, StartupCategory}
+
+// categoryPrefixes associates binary name prefixes to categories, for binaries outside of a category's directory.
+// A slice is used rather than a map so that prefixes are always checked in the same order.
+var categoryPrefixes = []struct {
+	prefix   string
+	category string
+}{
+	{prefix: "osu_put_", category: OneSidedCategory},
+	{prefix: "osu_get_", category: OneSidedCategory},
+	{prefix: "osu_acc_", category: OneSidedCategory},
+	{prefix: "osu_cas_", category: OneSidedCategory},
+	{prefix: "osu_fop_", category: OneSidedCategory},
+	{prefix: "osu_init", category: StartupCategory},
+	{prefix: "osu_hello", category: StartupCategory},
+	{prefix: "osu_latency", category: PointToPointCategory},
+	{prefix: "osu_bw", category: PointToPointCategory},
+	{prefix: "osu_bibw", category: PointToPointCategory},
+	{prefix: "osu_mbw", category: PointToPointCategory},
+	{prefix: "osu_multi", category: PointToPointCategory},
+}
+
+// collectiveOperations are the collective operations covered by the collective benchmarks, which binaries are
+// named after the operation, possibly with an "i" prefix for non-blocking collectives, e.g., osu_iallreduce,
+// and a "neighbor_" prefix for neighborhood collectives, e.g., osu_ineighbor_alltoall
+var collectiveOperations = []string{"allgather", "allreduce", "alltoall", "barrier", "bcast", "gather", "reduce", "scatter"}
+
+// subBenchmarkCategory infers the category of a sub-benchmark, first from the directory of its binary
+// and then from the name of the binary. Only the directory containing the binary is considered, so that
+// the directories the benchmarks are installed under do not matter. An empty string is returned when the
+// category cannot be inferred.
+func subBenchmarkCategory(info *app.Info) string {
+	if info.BinPath != "" {
+		dir := filepath.Base(filepath.Dir(info.BinPath))
+		for _, category := range categories {
+			if dir == category {
+				return category
+			}
+		}
+	}
+
+	name := info.BinName
+	if name == "" {
+		name = info.Name
+	}
+	for _, p := range categoryPrefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.category
+		}
+	}
+	if !strings.HasPrefix(name, "osu_") {
+		return ""
+	}
+	operation := strings.TrimPrefix(name, "osu_")
+	operation = strings.TrimPrefix(operation, "i")
+	operation = strings.TrimPrefix(operation, "neighbor_")
+	for _, op := range collectiveOperations {
+		if strings.HasPrefix(operation, op) {
+			return CollectiveCategory
+		}
+	}
+	return ""
+}
+
+// FilterByCategory returns the sub-benchmarks of a category, e.g., CollectiveCategory
+func (i *Install) FilterByCategory(category string) []app.Info {
+	var subBenchmarks []app.Info
+	for idx := range i.SubBenchmarks {
+		if subBenchmarkCategory(&i.SubBenchmarks[idx]) == category {
+			subBenchmarks = append(subBenchmarks, i.SubBenchmarks[idx])
+		}
+	}
+	return subBenchmarks
+}
+
+// RunAndParse runs a sub-benchmark with the given arguments, after the ones from its app.Info, and parses
+// its output. The sub-benchmark is killed if the context is canceled.
+func (i *Install) RunAndParse(ctx context.Context, name string, args []string) (*Result, error) {
+	subBenchmark, err := i.Find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmdArgs := append(append([]string{}, subBenchmark.BinArgs...), args...)
+	cmd := exec.CommandContext(ctx, subBenchmark.BinPath, cmdArgs...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%s failed: %w - stderr: %s", subBenchmark.BinPath, err, stderr.String())
+	}
+
+	result, _, err := ParseOSUOutput(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the output of %s: %w", subBenchmark.BinPath, err)
+	}
+	return result, nil
+}
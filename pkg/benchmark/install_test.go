@@ -0,0 +1,349 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gvallee/go_software_build/pkg/app"
+)
+
+func TestSaveLoadInstall(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	install := &Install{SubBenchmarks: []app.Info{
+		{Name: "osu_latency", BinName: "osu_latency", BinPath: "/opt/osu/mpi/pt2pt/osu_latency"},
+		{Name: "allreduce", BinName: "osu_allreduce", BinPath: "/opt/osu/mpi/collective/osu_allreduce", BinArgs: []string{"-f"}},
+	}}
+	path := filepath.Join(tempDir, "install.json")
+	err = install.Save(path)
+	if err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+	loaded, err := LoadInstall(path)
+	if err != nil {
+		t.Fatalf("LoadInstall() failed: %s", err)
+	}
+	if !reflect.DeepEqual(loaded, install) {
+		t.Fatalf("LoadInstall() returned %v, expected %v", loaded, install)
+	}
+
+	invalidPath := filepath.Join(tempDir, "invalid.json")
+	err = ioutil.WriteFile(invalidPath, []byte("{"), 0644)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", invalidPath, err)
+	}
+	for _, path := range []string{invalidPath, filepath.Join(tempDir, "missing.json")} {
+		_, err = LoadInstall(path)
+		if err == nil {
+			t.Fatalf("LoadInstall(%s) succeeded, expected an error", path)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	install := &Install{SubBenchmarks: []app.Info{
+		{Name: "latency", BinName: "osu_latency"},
+		{Name: "osu_bw", BinName: "osu_bw"},
+	}}
+
+	tests := []struct {
+		name         string
+		expectErr    bool
+		expectedName string
+	}{
+		{name: "latency", expectedName: "latency"},
+		{name: "osu_latency", expectedName: "latency"},
+		{name: "osu_bw", expectedName: "osu_bw"},
+		{name: "osu_bibw", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subBenchmark, err := install.Find(tt.name)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Find() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Find() failed: %s", err)
+			}
+			if subBenchmark.Name != tt.expectedName {
+				t.Fatalf("Find() returned %s, expected %s", subBenchmark.Name, tt.expectedName)
+			}
+			// The sub-benchmark is the one from the installation, not a copy
+			if subBenchmark != &install.SubBenchmarks[0] && subBenchmark != &install.SubBenchmarks[1] {
+				t.Fatalf("Find() returned a copy of the sub-benchmark")
+			}
+		})
+	}
+}
+
+func TestScanInstall(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []struct {
+		path string
+		perm os.FileMode
+	}{
+		{path: "libexec/osu-micro-benchmarks/mpi/collective/osu_allreduce", perm: 0755},
+		{path: "libexec/osu-micro-benchmarks/mpi/pt2pt/osu_latency", perm: 0755},
+		{path: "libexec/osu-micro-benchmarks/mpi/pt2pt/osu_latency.c", perm: 0644},
+		{path: "libexec/osu-micro-benchmarks/mpi/pt2pt/osu_bw.o", perm: 0644},
+		{path: "libexec/osu-micro-benchmarks/mpi/pt2pt/Makefile", perm: 0755},
+		// libtool keeps the actual binaries in .libs
+		{path: "libexec/osu-micro-benchmarks/mpi/pt2pt/.libs/osu_bw", perm: 0755},
+		// Same name in a later directory in lexical order
+		{path: "libexec/osu-micro-benchmarks/old/osu_latency", perm: 0755},
+	}
+	for _, f := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(f.path))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("unable to create %s: %s", filepath.Dir(path), err)
+		}
+		err = ioutil.WriteFile(path, []byte("#!/bin/sh\n"), f.perm)
+		if err != nil {
+			t.Fatalf("unable to create %s: %s", path, err)
+		}
+	}
+	// A symbolic link to an executable is not a regular file
+	err = os.Symlink("osu_latency", filepath.Join(tempDir, "libexec/osu-micro-benchmarks/mpi/pt2pt/osu_latency_link"))
+	if err != nil {
+		t.Fatalf("unable to create symbolic link: %s", err)
+	}
+
+	install, err := ScanInstall(tempDir)
+	if err != nil {
+		t.Fatalf("ScanInstall() failed: %s", err)
+	}
+	expected := []app.Info{
+		{
+			Name:    "osu_allreduce",
+			BinName: "osu_allreduce",
+			BinPath: filepath.Join(tempDir, "libexec/osu-micro-benchmarks/mpi/collective/osu_allreduce"),
+		},
+		{
+			Name:    "osu_latency",
+			BinName: "osu_latency",
+			BinPath: filepath.Join(tempDir, "libexec/osu-micro-benchmarks/mpi/pt2pt/osu_latency"),
+		},
+	}
+	if !reflect.DeepEqual(install.SubBenchmarks, expected) {
+		t.Fatalf("ScanInstall() found %v, expected %v", install.SubBenchmarks, expected)
+	}
+
+	_, err = ScanInstall(filepath.Join(tempDir, "missing"))
+	if err == nil {
+		t.Fatalf("ScanInstall() succeeded on a missing directory, expected an error")
+	}
+}
+
+func TestSubBenchmarkCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     app.Info
+		expected string
+	}{
+		{name: "latency", info: app.Info{BinName: "osu_latency"}, expected: PointToPointCategory},
+		{name: "multi-threaded latency", info: app.Info{BinName: "osu_latency_mt"}, expected: PointToPointCategory},
+		{name: "bandwidth", info: app.Info{BinName: "osu_bw"}, expected: PointToPointCategory},
+		{name: "bi-directional bandwidth", info: app.Info{BinName: "osu_bibw"}, expected: PointToPointCategory},
+		{name: "multiple bandwidth", info: app.Info{BinName: "osu_mbw_mr"}, expected: PointToPointCategory},
+		{name: "multiple latency", info: app.Info{BinName: "osu_multi_lat"}, expected: PointToPointCategory},
+		{name: "put bi-directional bandwidth", info: app.Info{BinName: "osu_put_bibw"}, expected: OneSidedCategory},
+		{name: "get accumulate latency", info: app.Info{BinName: "osu_get_acc_latency"}, expected: OneSidedCategory},
+		{name: "compare and swap", info: app.Info{BinName: "osu_cas_latency"}, expected: OneSidedCategory},
+		{name: "init", info: app.Info{BinName: "osu_init"}, expected: StartupCategory},
+		{name: "hello", info: app.Info{BinName: "osu_hello"}, expected: StartupCategory},
+		{name: "allreduce", info: app.Info{BinName: "osu_allreduce"}, expected: CollectiveCategory},
+		{name: "non-blocking allreduce", info: app.Info{BinName: "osu_iallreduce"}, expected: CollectiveCategory},
+		{name: "reduce scatter", info: app.Info{BinName: "osu_reduce_scatter"}, expected: CollectiveCategory},
+		{name: "allgatherv", info: app.Info{BinName: "osu_allgatherv"}, expected: CollectiveCategory},
+		{name: "non-blocking barrier", info: app.Info{BinName: "osu_ibarrier"}, expected: CollectiveCategory},
+		{name: "neighborhood allgather", info: app.Info{BinName: "osu_neighbor_allgather"}, expected: CollectiveCategory},
+		{
+			name:     "non-blocking neighborhood alltoall",
+			info:     app.Info{BinName: "osu_ineighbor_alltoall"},
+			expected: CollectiveCategory,
+		},
+		{
+			name:     "name only",
+			info:     app.Info{Name: "osu_put_latency"},
+			expected: OneSidedCategory,
+		},
+		{
+			name: "category directory",
+			info: app.Info{
+				BinName: "osu_neighbor_allgather",
+				BinPath: "/opt/osu/libexec/osu-micro-benchmarks/mpi/collective/osu_neighbor_allgather",
+			},
+			expected: CollectiveCategory,
+		},
+		{
+			name: "category directory takes precedence over the name",
+			info: app.Info{
+				BinName: "osu_new_benchmark",
+				BinPath: "/opt/osu/libexec/osu-micro-benchmarks/mpi/one-sided/osu_new_benchmark",
+			},
+			expected: OneSidedCategory,
+		},
+		{
+			name: "category in an ancestor directory",
+			info: app.Info{
+				BinName: "osu_latency",
+				BinPath: "/home/collective/osu/bin/osu_latency",
+			},
+			expected: PointToPointCategory,
+		},
+		{name: "unknown benchmark", info: app.Info{BinName: "osu_unknown"}, expected: ""},
+		{name: "not an OSU benchmark", info: app.Info{BinName: "allreduce"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := subBenchmarkCategory(&tt.info)
+			if category != tt.expected {
+				t.Fatalf("subBenchmarkCategory() returned %q, expected %q", category, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterByCategory(t *testing.T) {
+	install := &Install{
+		SubBenchmarks: []app.Info{
+			{Name: "osu_latency", BinName: "osu_latency", BinPath: "/osu/mpi/pt2pt/osu_latency"},
+			{Name: "osu_allreduce", BinName: "osu_allreduce", BinPath: "/osu/mpi/collective/osu_allreduce"},
+			{Name: "osu_put_bibw", BinName: "osu_put_bibw", BinPath: "/osu/bin/osu_put_bibw"},
+			{Name: "osu_reduce_scatter", BinName: "osu_reduce_scatter", BinPath: "/osu/bin/osu_reduce_scatter"},
+		},
+	}
+
+	tests := []struct {
+		category string
+		expected []string
+	}{
+		{category: PointToPointCategory, expected: []string{"osu_latency"}},
+		{category: CollectiveCategory, expected: []string{"osu_allreduce", "osu_reduce_scatter"}},
+		{category: OneSidedCategory, expected: []string{"osu_put_bibw"}},
+		{category: StartupCategory, expected: nil},
+		{category: "unknown", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			var names []string
+			for _, subBenchmark := range install.FilterByCategory(tt.category) {
+				names = append(names, subBenchmark.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Fatalf("FilterByCategory(%q) returned %v, expected %v", tt.category, names, tt.expected)
+			}
+		})
+	}
+}
+
+func writeTestScript(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+content), 0755)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", path, err)
+	}
+	return path
+}
+
+func TestRunAndParse(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// The script only succeeds when given the arguments from its app.Info followed by the ones from the call
+	latencyPath := writeTestScript(t, tempDir, "osu_latency", `[ "$*" = "-m 2 -i 10" ] || exit 2
+cat <<'EOF'
+`+osuLatencyOutput+`EOF
+`)
+	failurePath := writeTestScript(t, tempDir, "osu_bw", "echo 'MPI_Init failed' >&2\nexit 1\n")
+	garbagePath := writeTestScript(t, tempDir, "osu_bibw", "echo 'not a benchmark output'\n")
+	hangPath := writeTestScript(t, tempDir, "osu_barrier", "exec sleep 10\n")
+	install := &Install{
+		SubBenchmarks: []app.Info{
+			{Name: "osu_latency", BinName: "osu_latency", BinPath: latencyPath, BinArgs: []string{"-m", "2"}},
+			{Name: "osu_bw", BinName: "osu_bw", BinPath: failurePath},
+			{Name: "osu_bibw", BinName: "osu_bibw", BinPath: garbagePath},
+			{Name: "osu_barrier", BinName: "osu_barrier", BinPath: hangPath},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		result, err := install.RunAndParse(context.Background(), "osu_latency", []string{"-i", "10"})
+		if err != nil {
+			t.Fatalf("RunAndParse() failed: %s", err)
+		}
+		expected := []*DataPoint{{Size: 0, Value: 1.5}, {Size: 1, Value: 1.6}, {Size: 2, Value: 1.7}}
+		if !reflect.DeepEqual(result.DataPoints, expected) {
+			t.Fatalf("RunAndParse() returned %v, expected %v", result.DataPoints, expected)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := install.RunAndParse(context.Background(), "osu_bw", nil)
+		if err == nil {
+			t.Fatalf("RunAndParse() succeeded, expected an error")
+		}
+		if !strings.Contains(err.Error(), "MPI_Init failed") {
+			t.Fatalf("RunAndParse() returned %q, expected the error to include stderr", err)
+		}
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := install.RunAndParse(context.Background(), "osu_bibw", nil)
+		if err == nil {
+			t.Fatalf("RunAndParse() succeeded, expected an error")
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := install.RunAndParse(ctx, "osu_barrier", nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("RunAndParse() returned %v, expected %v", err, context.DeadlineExceeded)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("RunAndParse() did not kill the sub-benchmark")
+		}
+	})
+
+	t.Run("unknown sub-benchmark", func(t *testing.T) {
+		_, err := install.RunAndParse(context.Background(), "osu_unknown", nil)
+		if err == nil {
+			t.Fatalf("RunAndParse() succeeded, expected an error")
+		}
+	})
+}
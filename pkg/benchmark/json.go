@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonDataPoint is the JSON representation of a DataPoint
+type jsonDataPoint struct {
+	Size  float64            `json:"size"`
+	Value float64            `json:"value"`
+	Extra map[string]float64 `json:"extra,omitempty"`
+}
+
+// MarshalJSON encodes the results as an array of results, each result being an array of data points.
+// Undefined results and data points cannot be encoded and are reported as errors.
+func (r *Results) MarshalJSON() ([]byte, error) {
+	var results [][]*jsonDataPoint
+	if r.Result != nil {
+		results = make([][]*jsonDataPoint, len(r.Result))
+	}
+	for i, result := range r.Result {
+		if result == nil {
+			return nil, fmt.Errorf("undefined result %d", i)
+		}
+		if result.DataPoints == nil {
+			continue
+		}
+		results[i] = make([]*jsonDataPoint, len(result.DataPoints))
+		for j, dp := range result.DataPoints {
+			if dp == nil {
+				return nil, fmt.Errorf("result %d: undefined data point %d", i, j)
+			}
+			results[i][j] = &jsonDataPoint{Size: dp.Size, Value: dp.Value, Extra: dp.Extra}
+		}
+	}
+	return json.Marshal(results)
+}
+
+// LoadResultsJSON loads results that were previously encoded with Results.MarshalJSON
+func LoadResultsJSON(r io.Reader) (*Results, error) {
+	var results [][]*jsonDataPoint
+	err := json.NewDecoder(r).Decode(&results)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode results: %w", err)
+	}
+
+	loadedResults := new(Results)
+	if results != nil {
+		loadedResults.Result = make([]*Result, len(results))
+	}
+	for i, result := range results {
+		loadedResults.Result[i] = new(Result)
+		if result == nil {
+			continue
+		}
+		loadedResults.Result[i].DataPoints = make([]*DataPoint, len(result))
+		for j, dp := range result {
+			if dp == nil {
+				return nil, fmt.Errorf("result %d: undefined data point %d", i, j)
+			}
+			loadedResults.Result[i].DataPoints[j] = &DataPoint{Size: dp.Size, Value: dp.Value, Extra: dp.Extra}
+		}
+	}
+	return loadedResults, nil
+}
@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResultsJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   *Results
+		expectErr bool
+	}{
+		{
+			name:    "no results",
+			results: &Results{},
+		},
+		{
+			name: "results with extra values",
+			results: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{
+					{Size: 1, Value: 1.5, Extra: map[string]float64{ExtraMin: 1, ExtraMax: 2}},
+					{Size: 2, Value: 2.5},
+				}},
+				{DataPoints: []*DataPoint{
+					{Size: 1, Value: 3},
+				}},
+			}},
+		},
+		{
+			name:    "result without data points",
+			results: &Results{Result: []*Result{{}}},
+		},
+		{
+			name:      "undefined result",
+			results:   &Results{Result: []*Result{nil}},
+			expectErr: true,
+		},
+		{
+			name:      "undefined data point",
+			results:   &Results{Result: []*Result{{DataPoints: []*DataPoint{nil}}}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.results)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("json.Marshal() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("json.Marshal() failed: %s", err)
+			}
+			loaded, err := LoadResultsJSON(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("LoadResultsJSON() failed: %s", err)
+			}
+			if !reflect.DeepEqual(loaded, tt.results) {
+				t.Fatalf("results do not round-trip: got %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,418 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// LatencyBenchmarkType is the type of the benchmarks measuring latency, e.g., osu_latency
+	LatencyBenchmarkType = "latency"
+
+	// BandwidthBenchmarkType is the type of the benchmarks measuring uni-directional bandwidth, e.g., osu_bw
+	BandwidthBenchmarkType = "bandwidth"
+
+	// BiBandwidthBenchmarkType is the type of the benchmarks measuring bi-directional bandwidth, e.g., osu_bibw
+	BiBandwidthBenchmarkType = "bibw"
+)
+
+const (
+	// ExtraAvg is the key of the average value in DataPoint.Extra
+	ExtraAvg = "avg"
+
+	// ExtraMin is the key of the minimum value in DataPoint.Extra
+	ExtraMin = "min"
+
+	// ExtraMax is the key of the maximum value in DataPoint.Extra
+	ExtraMax = "max"
+)
+
+const (
+	// metadataBenchmarkType is the prefix of the metadata giving the type of benchmark, see ParseOSUOutput
+	metadataBenchmarkType = "Benchmark type: "
+
+	// metadataUnits is the prefix of the metadata giving the units of the values, see ParseOSUOutput
+	metadataUnits = "Units: "
+)
+
+// ParseError is the error returned when the output of a benchmark cannot be parsed
+type ParseError struct {
+	// Line is the number of the line where the error occurred, starting at 1
+	Line int
+
+	// Field is the name of the invalid field, e.g., "size" or "value"; empty when the line as a whole is invalid
+	Field string
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: invalid %s: %s", e.Line, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// streamOSUDataLines parses the output of an OSU benchmark which is composed of comment lines starting
+// with '#' and lines with a size and a value, calling fn for each data point; parsing stops on the first
+// error returned by fn. When allowExtraFields is true, fields after the size and the value are ignored,
+// otherwise they are considered as an error.
+// When columns is not nil, or when the column header reports average, minimum and maximum values (e.g., OSU
+// collectives with the -f option), each line has a size followed by the values of the columns, in that order:
+// the values are stored in Extra and the value of the data point is the average value; additional fields,
+// e.g., the number of iterations, are ignored.
+func streamOSUDataLines(r io.Reader, allowExtraFields bool, columns []string, fn func(dp *DataPoint) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			header := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if strings.HasPrefix(header, "Size") {
+				headerColumns := fullLatencyColumns(header)
+				if headerColumns != nil {
+					columns = headerColumns
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if columns != nil {
+			if len(fields) < len(columns)+1 {
+				return &ParseError{Line: lineNum, Err: fmt.Errorf("expected at least %d fields but got %d", len(columns)+1, len(fields))}
+			}
+		} else if len(fields) < 2 || (len(fields) > 2 && !allowExtraFields) {
+			return &ParseError{Line: lineNum, Err: fmt.Errorf("expected 2 fields but got %d", len(fields))}
+		}
+		size, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return &ParseError{Line: lineNum, Field: "size", Err: err}
+		}
+		dp := &DataPoint{Size: size}
+		if columns != nil {
+			dp.Extra = make(map[string]float64)
+			for i, column := range columns {
+				value, err := strconv.ParseFloat(fields[i+1], 64)
+				if err != nil {
+					return &ParseError{Line: lineNum, Field: column, Err: err}
+				}
+				dp.Extra[column] = value
+			}
+			dp.Value = dp.Extra[ExtraAvg]
+		} else {
+			dp.Value, err = strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return &ParseError{Line: lineNum, Field: "value", Err: err}
+			}
+		}
+		err = fn(dp)
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseOSUDataLines parses the output of an OSU benchmark into a result, see streamOSUDataLines
+func parseOSUDataLines(r io.Reader, allowExtraFields bool, columns []string) (*Result, error) {
+	result := new(Result)
+	err := streamOSUDataLines(r, allowExtraFields, columns, func(dp *DataPoint) error {
+		result.DataPoints = append(result.DataPoints, dp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParseOSULatencyOutput parses the output of osu_latency, i.e., sizes in bytes and latencies in microseconds.
+// Outputs reporting the average, minimum and maximum latencies are parsed as with ParseOSULatencyFullOutput.
+func ParseOSULatencyOutput(r io.Reader) (*Result, error) {
+	return parseOSUDataLines(r, false, nil)
+}
+
+// fullLatencyColumns returns the order of the average, minimum and maximum latency columns based on the
+// column header of an OSU benchmark, e.g., "Size       Avg Latency(us)   Min Latency(us)   Max Latency(us)",
+// or nil if the header does not have the three columns
+func fullLatencyColumns(header string) []string {
+	columns := []string{ExtraAvg, ExtraMin, ExtraMax}
+	lowerHeader := strings.ToLower(header)
+	for _, column := range columns {
+		if !strings.Contains(lowerHeader, column) {
+			return nil
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return strings.Index(lowerHeader, columns[i]) < strings.Index(lowerHeader, columns[j])
+	})
+	return columns
+}
+
+// ParseOSULatencyFullOutput parses the output of OSU latency benchmarks reporting the average, minimum and
+// maximum latencies, e.g., collectives with the -f option. The value of the data points is the average latency
+// and Extra gives the three latencies with the ExtraAvg, ExtraMin and ExtraMax keys. Additional columns,
+// e.g., the number of iterations, are ignored. Without a column header, the average, minimum and maximum
+// latencies are assumed to be in that order.
+func ParseOSULatencyFullOutput(r io.Reader) (*Result, error) {
+	return parseOSUDataLines(r, true, []string{ExtraAvg, ExtraMin, ExtraMax})
+}
+
+// ParseOSUBandwidthOutput parses the output of osu_bw and osu_bibw, i.e., sizes in bytes and bandwidths in MB/s.
+// Some versions of OSU emit extra columns, these are ignored.
+func ParseOSUBandwidthOutput(r io.Reader) (*Result, error) {
+	return parseOSUDataLines(r, true, nil)
+}
+
+// readOSUHeader returns the lines of the header of the output of an OSU benchmark, i.e., the lines starting
+// with '#' before the first data line, without the leading '#'
+func readOSUHeader(r io.Reader) ([]string, error) {
+	var header []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			// End of the header
+			break
+		}
+		header = append(header, strings.TrimSpace(strings.TrimLeft(line, "#")))
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// DetectBenchmarkType inspects the header of the output of an OSU benchmark and returns the type of the
+// benchmark: LatencyBenchmarkType, BandwidthBenchmarkType or BiBandwidthBenchmarkType
+func DetectBenchmarkType(r io.Reader) (string, error) {
+	header, err := readOSUHeader(r)
+	if err != nil {
+		return "", err
+	}
+
+	headerText := strings.ToLower(strings.Join(header, "\n"))
+	switch {
+	case strings.Contains(headerText, "bi-directional") || strings.Contains(headerText, "bidirectional"):
+		return BiBandwidthBenchmarkType, nil
+	case strings.Contains(headerText, "bandwidth") || strings.Contains(headerText, "mb/s"):
+		return BandwidthBenchmarkType, nil
+	case strings.Contains(headerText, "latency") || strings.Contains(headerText, "(us)"):
+		return LatencyBenchmarkType, nil
+	}
+	return "", fmt.Errorf("unable to detect the type of benchmark from the output header")
+}
+
+// isOSUHeaderKey checks whether a string can be the key of a "key: value" line of the header of the
+// output of an OSU benchmark, i.e., words made of letters, digits, '-' and '_'
+func isOSUHeaderKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != ' ' && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// addOSUHeaderPair adds the details of a "key: value" string to details if the key is valid
+func addOSUHeaderPair(details map[string]string, pair string) {
+	idx := strings.Index(pair, ":")
+	if idx == -1 {
+		return
+	}
+	key := strings.ToLower(strings.TrimSpace(pair[:idx]))
+	if isOSUHeaderKey(key) {
+		details[key] = strings.TrimSpace(pair[idx+1:])
+	}
+}
+
+// ParseOSUHeader extracts the details from the header of the output of an OSU benchmark:
+// "title" for the name of the test (e.g., "OSU MPI Latency Test"), "version" when the title includes one,
+// "units" for the units of the values (e.g., "us"), "key: value" lines such as "Datatype: MPI_CHAR" and
+// "[ key: value ]" groups such as "[ pairs: 1 ] [ window size: 64 ]", with the keys in lower case
+func ParseOSUHeader(r io.Reader) (map[string]string, error) {
+	header, err := readOSUHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 {
+		return nil, fmt.Errorf("no header")
+	}
+
+	details := make(map[string]string)
+	for _, line := range header {
+		switch {
+		case strings.HasPrefix(strings.ToUpper(line), "OSU "):
+			title := line
+			idx := strings.LastIndex(title, " v")
+			if idx != -1 && idx+2 < len(title) && title[idx+2] >= '0' && title[idx+2] <= '9' {
+				details["version"] = title[idx+2:]
+				title = strings.TrimSpace(title[:idx])
+			}
+			details["title"] = title
+		case strings.HasPrefix(line, "Size"):
+			// Column header, e.g., "Size          Latency (us)" or "Size      MB/s        Messages/s"
+			start := strings.LastIndex(line, "(")
+			end := strings.LastIndex(line, ")")
+			if start != -1 && end > start {
+				details["units"] = strings.TrimSpace(line[start+1 : end])
+			} else if fields := strings.Fields(line); len(fields) > 1 {
+				details["units"] = fields[1]
+			}
+		case strings.HasPrefix(line, "["):
+			// Groups of parameters, e.g., "[ pairs: 1 ] [ window size: 64 ]"
+			for _, group := range strings.Split(line, "]") {
+				addOSUHeaderPair(details, strings.TrimPrefix(strings.TrimSpace(group), "["))
+			}
+		default:
+			addOSUHeaderPair(details, line)
+		}
+	}
+	return details, nil
+}
+
+// ParseOSUOutput parses the output of any supported OSU benchmark. The type of benchmark is detected
+// from the header of the output and is reported, with the units of the values, in the returned metadata.
+func ParseOSUOutput(r io.Reader) (*Result, *SpreadsheetMetadata, error) {
+	output, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	benchmarkType, err := DetectBenchmarkType(bytes.NewReader(output))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result *Result
+	switch benchmarkType {
+	case LatencyBenchmarkType:
+		result, err = ParseOSULatencyOutput(bytes.NewReader(output))
+	case BandwidthBenchmarkType, BiBandwidthBenchmarkType:
+		result, err = ParseOSUBandwidthOutput(bytes.NewReader(output))
+	default:
+		err = fmt.Errorf("unsupported benchmark type %s", benchmarkType)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := ParseOSUHeader(bytes.NewReader(output))
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := new(SpreadsheetMetadata)
+	metadata.Content = append(metadata.Content, metadataBenchmarkType+benchmarkType)
+	if header["units"] != "" {
+		metadata.Content = append(metadata.Content, metadataUnits+header["units"])
+	}
+	return result, metadata, nil
+}
+
+// ParseOSUOutputFile parses the output of an OSU benchmark from a file, which is transparently
+// decompressed when gzipped
+func ParseOSUOutputFile(path string) (*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	var r io.Reader = br
+	if strings.HasSuffix(path, ".gz") || bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress %s: %w", path, err)
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+
+	result, _, err := ParseOSUOutput(r)
+	return result, err
+}
+
+// ParseOSUOutputFiles parses the output of OSU benchmarks from multiple files, using a pool of workers.
+// The results are in the same order as the files.
+func ParseOSUOutputFiles(paths []string, workers int) (*Results, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid number of workers: %d", workers)
+	}
+
+	results := make([]*Result, len(paths))
+	errs := make([]error, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = ParseOSUOutputFile(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", paths[i], err)
+		}
+	}
+	return &Results{Result: results}, nil
+}
+
+// StreamOSUOutput parses the output of an OSU benchmark of a given type (e.g., LatencyBenchmarkType) and calls
+// fn for each data point instead of keeping them in memory. Parsing stops on the first error returned by fn.
+func StreamOSUOutput(r io.Reader, kind string, fn func(dp *DataPoint) error) error {
+	switch kind {
+	case LatencyBenchmarkType:
+		return streamOSUDataLines(r, false, nil, fn)
+	case BandwidthBenchmarkType, BiBandwidthBenchmarkType:
+		return streamOSUDataLines(r, true, nil, fn)
+	}
+	return fmt.Errorf("unsupported benchmark type %s", kind)
+}
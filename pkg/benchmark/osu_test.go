@@ -0,0 +1,739 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	osuLatencyOutput = `# OSU MPI Latency Test v5.8
+# Size          Latency (us)
+0                       1.50
+1                       1.60
+2                       1.70
+`
+
+	osuBandwidthOutput = `# OSU MPI Bandwidth Test v5.8
+# Size      Bandwidth (MB/s)
+1                       2.50
+2                       5.00
+`
+
+	osuBiBandwidthOutput = `# OSU MPI Bi-Directional Bandwidth Test v5.8
+# Size      Bandwidth (MB/s)
+1                       4.00
+`
+
+	osuAllreduceFullOutput = `# OSU MPI Allreduce Latency Test v5.8
+# Size       Avg Latency(us)   Min Latency(us)   Max Latency(us)  Iterations
+4                       2.00              1.00              3.00        1000
+8                       2.50              1.50              3.50        1000
+`
+
+	osuReorderedFullOutput = `# OSU MPI Allreduce Latency Test v5.8
+# Size       Min Latency(us)   Max Latency(us)   Avg Latency(us)  Iterations
+4                       1.00              3.00              2.00        1000
+`
+)
+
+func fullDataPoint(size, avg, min, max float64) *DataPoint {
+	return &DataPoint{Size: size, Value: avg, Extra: map[string]float64{ExtraAvg: avg, ExtraMin: min, ExtraMax: max}}
+}
+
+func TestParseOSULatencyOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []*DataPoint
+	}{
+		{
+			name:     "osu_latency",
+			output:   osuLatencyOutput,
+			expected: []*DataPoint{{Size: 0, Value: 1.5}, {Size: 1, Value: 1.6}, {Size: 2, Value: 1.7}},
+		},
+		{
+			name:     "without header",
+			output:   "1 1.5\n2 1.7\n",
+			expected: []*DataPoint{{Size: 1, Value: 1.5}, {Size: 2, Value: 1.7}},
+		},
+		{
+			name:     "blank lines and comments",
+			output:   "\n# OSU MPI Latency Test v5.8\n\n1    1.5\n# Warning\n\t2\t1.7  \n",
+			expected: []*DataPoint{{Size: 1, Value: 1.5}, {Size: 2, Value: 1.7}},
+		},
+		{
+			name:   "empty output",
+			output: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseOSULatencyOutput(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatalf("ParseOSULatencyOutput() failed: %s", err)
+			}
+			if !reflect.DeepEqual(result.DataPoints, tt.expected) {
+				t.Fatalf("ParseOSULatencyOutput() returned %v, expected %v", result.DataPoints, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOSUBandwidthOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []*DataPoint
+	}{
+		{
+			name:     "osu_bw",
+			output:   osuBandwidthOutput,
+			expected: []*DataPoint{{Size: 1, Value: 2.5}, {Size: 2, Value: 5}},
+		},
+		{
+			name:     "osu_bibw",
+			output:   osuBiBandwidthOutput,
+			expected: []*DataPoint{{Size: 1, Value: 4}},
+		},
+		{
+			name: "extra columns",
+			output: `# OSU MPI Multiple Bandwidth / Message Rate Test v5.8
+# [ pairs: 1 ] [ window size: 64 ]
+# Size                  MB/s        Messages/s
+1                       2.93        2930000.20
+2                       5.86        2930000.10
+`,
+			expected: []*DataPoint{{Size: 1, Value: 2.93}, {Size: 2, Value: 5.86}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseOSUBandwidthOutput(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatalf("ParseOSUBandwidthOutput() failed: %s", err)
+			}
+			if !reflect.DeepEqual(result.DataPoints, tt.expected) {
+				t.Fatalf("ParseOSUBandwidthOutput() returned %v, expected %v", result.DataPoints, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectBenchmarkType(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		expectErr bool
+		expected  string
+	}{
+		{name: "latency", output: osuLatencyOutput, expected: LatencyBenchmarkType},
+		{name: "full latency", output: osuAllreduceFullOutput, expected: LatencyBenchmarkType},
+		{name: "bandwidth", output: osuBandwidthOutput, expected: BandwidthBenchmarkType},
+		{name: "bi-directional bandwidth", output: osuBiBandwidthOutput, expected: BiBandwidthBenchmarkType},
+		{
+			name:     "units only",
+			output:   "# Size          MB/s\n1 2.5\n",
+			expected: BandwidthBenchmarkType,
+		},
+		{
+			name:      "header after the data",
+			output:    "1 1.5\n# OSU MPI Latency Test v5.8\n",
+			expectErr: true,
+		},
+		{
+			name:      "unknown benchmark",
+			output:    "# OSU MPI Hello World Test v5.8\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			benchmarkType, err := DetectBenchmarkType(strings.NewReader(tt.output))
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("DetectBenchmarkType() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectBenchmarkType() failed: %s", err)
+			}
+			if benchmarkType != tt.expected {
+				t.Fatalf("DetectBenchmarkType() returned %q, expected %q", benchmarkType, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOSUOutput(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		expectedType  string
+		expectedUnits string
+		expected      []*DataPoint
+	}{
+		{
+			name:          "latency",
+			output:        osuLatencyOutput,
+			expectedType:  LatencyBenchmarkType,
+			expectedUnits: "us",
+			expected:      []*DataPoint{{Size: 0, Value: 1.5}, {Size: 1, Value: 1.6}, {Size: 2, Value: 1.7}},
+		},
+		{
+			name:          "bandwidth",
+			output:        osuBandwidthOutput,
+			expectedType:  BandwidthBenchmarkType,
+			expectedUnits: "MB/s",
+			expected:      []*DataPoint{{Size: 1, Value: 2.5}, {Size: 2, Value: 5}},
+		},
+		{
+			name:          "bi-directional bandwidth",
+			output:        osuBiBandwidthOutput,
+			expectedType:  BiBandwidthBenchmarkType,
+			expectedUnits: "MB/s",
+			expected:      []*DataPoint{{Size: 1, Value: 4}},
+		},
+		{
+			name:          "full latency",
+			output:        osuAllreduceFullOutput,
+			expectedType:  LatencyBenchmarkType,
+			expectedUnits: "us",
+			expected:      []*DataPoint{fullDataPoint(4, 2, 1, 3), fullDataPoint(8, 2.5, 1.5, 3.5)},
+		},
+		{
+			name:          "full latency with reordered columns",
+			output:        osuReorderedFullOutput,
+			expectedType:  LatencyBenchmarkType,
+			expectedUnits: "us",
+			expected:      []*DataPoint{fullDataPoint(4, 2, 1, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, metadata, err := ParseOSUOutput(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatalf("ParseOSUOutput() failed: %s", err)
+			}
+			if !reflect.DeepEqual(result.DataPoints, tt.expected) {
+				t.Fatalf("ParseOSUOutput() returned unexpected data points")
+			}
+			expectedContent := []string{"Benchmark type: " + tt.expectedType, "Units: " + tt.expectedUnits}
+			if !reflect.DeepEqual(metadata.Content, expectedContent) {
+				t.Fatalf("ParseOSUOutput() returned %v as metadata, expected %v", metadata.Content, expectedContent)
+			}
+		})
+	}
+}
+
+func TestStreamOSUOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		kind     string
+		expected []*DataPoint
+	}{
+		{
+			name:     "latency",
+			output:   osuLatencyOutput,
+			kind:     LatencyBenchmarkType,
+			expected: []*DataPoint{{Size: 0, Value: 1.5}, {Size: 1, Value: 1.6}, {Size: 2, Value: 1.7}},
+		},
+		{
+			name:     "full latency",
+			output:   osuAllreduceFullOutput,
+			kind:     LatencyBenchmarkType,
+			expected: []*DataPoint{fullDataPoint(4, 2, 1, 3), fullDataPoint(8, 2.5, 1.5, 3.5)},
+		},
+		{
+			name:     "bandwidth",
+			output:   osuBandwidthOutput,
+			kind:     BandwidthBenchmarkType,
+			expected: []*DataPoint{{Size: 1, Value: 2.5}, {Size: 2, Value: 5}},
+		},
+		{
+			name:     "bi-directional bandwidth",
+			output:   osuBiBandwidthOutput,
+			kind:     BiBandwidthBenchmarkType,
+			expected: []*DataPoint{{Size: 1, Value: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streamed []*DataPoint
+			err := StreamOSUOutput(strings.NewReader(tt.output), tt.kind, func(dp *DataPoint) error {
+				streamed = append(streamed, dp)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamOSUOutput() failed: %s", err)
+			}
+			if !reflect.DeepEqual(streamed, tt.expected) {
+				t.Fatalf("StreamOSUOutput() returned %v, expected %v", streamed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStreamOSUOutputStop(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	err := StreamOSUOutput(strings.NewReader(osuLatencyOutput), LatencyBenchmarkType, func(dp *DataPoint) error {
+		calls++
+		if dp.Size == 1 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("StreamOSUOutput() returned %v, expected the error of the callback", err)
+	}
+	if calls != 2 {
+		t.Fatalf("the callback was called %d times, expected 2", calls)
+	}
+
+	err = StreamOSUOutput(strings.NewReader(osuLatencyOutput), "unknown", func(dp *DataPoint) error { return nil })
+	if err == nil {
+		t.Fatalf("StreamOSUOutput() succeeded with an unknown type of benchmark, expected an error")
+	}
+}
+
+func TestParseOSUOutputInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{name: "empty output", output: ""},
+		{name: "no header", output: "1 1.5\n"},
+		{name: "unknown benchmark", output: "# OSU MPI Hello World Test v5.8\n"},
+		{name: "invalid data", output: "# OSU MPI Latency Test v5.8\n# Size          Latency (us)\n1 x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseOSUOutput(strings.NewReader(tt.output))
+			if err == nil {
+				t.Fatalf("ParseOSUOutput() succeeded, expected an error")
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name          string
+		parse         func(output string) error
+		output        string
+		expectedLine  int
+		expectedField string
+	}{
+		{
+			name: "latency with extra fields",
+			parse: func(output string) error {
+				_, err := ParseOSULatencyOutput(strings.NewReader(output))
+				return err
+			},
+			output:       "# Size          Latency (us)\n1 1.5 2\n",
+			expectedLine: 2,
+		},
+		{
+			name: "invalid size",
+			parse: func(output string) error {
+				_, err := ParseOSULatencyOutput(strings.NewReader(output))
+				return err
+			},
+			output:        "1 1.5\nx 1.5\n",
+			expectedLine:  2,
+			expectedField: "size",
+		},
+		{
+			name: "invalid bandwidth",
+			parse: func(output string) error {
+				_, err := ParseOSUBandwidthOutput(strings.NewReader(output))
+				return err
+			},
+			output:        "1 x\n",
+			expectedLine:  1,
+			expectedField: "value",
+		},
+		{
+			name: "missing full latency fields",
+			parse: func(output string) error {
+				_, err := ParseOSULatencyFullOutput(strings.NewReader(output))
+				return err
+			},
+			output:       "4 2.0 1.0\n",
+			expectedLine: 1,
+		},
+		{
+			name: "invalid maximum latency",
+			parse: func(output string) error {
+				_, err := ParseOSULatencyFullOutput(strings.NewReader(output))
+				return err
+			},
+			output:        "4 2.0 1.0 x\n",
+			expectedLine:  1,
+			expectedField: ExtraMax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse(tt.output)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+			if parseErr.Line != tt.expectedLine || parseErr.Field != tt.expectedField {
+				t.Fatalf("got an error on line %d for field %q, expected line %d and field %q", parseErr.Line, parseErr.Field, tt.expectedLine, tt.expectedField)
+			}
+			if !strings.HasPrefix(err.Error(), fmt.Sprintf("line %d: ", tt.expectedLine)) {
+				t.Fatalf("error %q does not start with the line number", err)
+			}
+			// Invalid fields wrap the conversion error
+			var numErr *strconv.NumError
+			if errors.As(err, &numErr) != (tt.expectedField != "") {
+				t.Fatalf("error %q unexpectedly wraps or does not wrap a conversion error", err)
+			}
+		})
+	}
+}
+
+func TestParseOSULatencyFullOutputWithoutHeader(t *testing.T) {
+	result, err := ParseOSULatencyFullOutput(strings.NewReader("4 2.0 1.0 3.0 1000\n"))
+	if err != nil {
+		t.Fatalf("ParseOSULatencyFullOutput() failed: %s", err)
+	}
+	expected := []*DataPoint{fullDataPoint(4, 2, 1, 3)}
+	if !reflect.DeepEqual(result.DataPoints, expected) {
+		t.Fatalf("ParseOSULatencyFullOutput() returned unexpected data points")
+	}
+}
+
+func TestTransformsKeepExtra(t *testing.T) {
+	r := &Result{DataPoints: []*DataPoint{fullDataPoint(1024, 2, 1, 3), fullDataPoint(1024, 4, 3, 5)}}
+
+	converted, err := r.ConvertSizes("KiB")
+	if err != nil {
+		t.Fatalf("ConvertSizes() failed: %s", err)
+	}
+	if !reflect.DeepEqual(converted.DataPoints[0], fullDataPoint(1, 2, 1, 3)) {
+		t.Fatalf("ConvertSizes() did not keep the extra values")
+	}
+
+	tests := []struct {
+		strategy string
+		expected *DataPoint
+	}{
+		{strategy: "first", expected: fullDataPoint(1024, 2, 1, 3)},
+		{strategy: "last", expected: fullDataPoint(1024, 4, 3, 5)},
+		{strategy: "mean", expected: &DataPoint{Size: 1024, Value: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			deduplicated, err := r.Deduplicate(tt.strategy)
+			if err != nil {
+				t.Fatalf("Deduplicate() failed: %s", err)
+			}
+			if len(deduplicated.DataPoints) != 1 || !reflect.DeepEqual(deduplicated.DataPoints[0], tt.expected) {
+				t.Fatalf("Deduplicate() returned unexpected data points")
+			}
+			if deduplicated.DataPoints[0].Extra == nil {
+				return
+			}
+			deduplicated.DataPoints[0].Extra[ExtraAvg] = -1
+			if r.DataPoints[0].Extra[ExtraAvg] == -1 || r.DataPoints[1].Extra[ExtraAvg] == -1 {
+				t.Fatalf("Deduplicate() modified the original result")
+			}
+		})
+	}
+}
+
+func TestValueTransformsKeepExtra(t *testing.T) {
+	r := &Result{DataPoints: []*DataPoint{fullDataPoint(1000, 2000, 1000, 4000)}}
+
+	converted, err := r.ConvertValues("us", "ms")
+	if err != nil {
+		t.Fatalf("ConvertValues() failed: %s", err)
+	}
+	if !reflect.DeepEqual(converted.DataPoints, []*DataPoint{fullDataPoint(1000, 2, 1, 4)}) {
+		t.Fatalf("ConvertValues() did not convert the extra values")
+	}
+	max, err := converted.Select(ExtraMax)
+	if err != nil {
+		t.Fatalf("Select() failed after ConvertValues(): %s", err)
+	}
+	if max.DataPoints[0].Value != 4 {
+		t.Fatalf("Select() returned %f, expected 4", max.DataPoints[0].Value)
+	}
+
+	bandwidth, err := r.DeriveBandwidth()
+	if err != nil {
+		t.Fatalf("DeriveBandwidth() failed: %s", err)
+	}
+	// The maximum latency gives the minimum bandwidth
+	if !reflect.DeepEqual(bandwidth.DataPoints, []*DataPoint{fullDataPoint(1000, 0.5, 0.25, 1)}) {
+		t.Fatalf("DeriveBandwidth() returned unexpected data points: %v", bandwidth.DataPoints[0])
+	}
+
+	_, err = (&Result{DataPoints: []*DataPoint{fullDataPoint(1000, 2000, 0, 4000)}}).DeriveBandwidth()
+	if err == nil {
+		t.Fatalf("DeriveBandwidth() succeeded with a zero minimum latency, expected an error")
+	}
+}
+
+func TestExportExtraColumns(t *testing.T) {
+	result, err := ParseOSULatencyFullOutput(strings.NewReader(osuAllreduceFullOutput))
+	if err != nil {
+		t.Fatalf("ParseOSULatencyFullOutput() failed: %s", err)
+	}
+	data := &SpreadsheetData{
+		Labels: []string{"allreduce", "plain"},
+		Data:   &Results{Result: []*Result{result, {DataPoints: []*DataPoint{{Size: 4, Value: 7}}}}},
+	}
+	opts := &ExportOptions{ExtraColumns: []string{ExtraMin, ExtraMax}}
+
+	var csv bytes.Buffer
+	err = ExportCSV(&csv, data, opts)
+	if err != nil {
+		t.Fatalf("ExportCSV() failed: %s", err)
+	}
+	expectedCSV := ",allreduce,allreduce (min),allreduce (max),plain,plain (min),plain (max)\n" +
+		"4,2,1,3,7,,\n" +
+		"8,2.5,1.5,3.5,,,\n"
+	if csv.String() != expectedCSV {
+		t.Fatalf("ExportCSV() wrote %q, expected %q", csv.String(), expectedCSV)
+	}
+
+	var tsv bytes.Buffer
+	err = ExportTSV(&tsv, data, opts)
+	if err != nil {
+		t.Fatalf("ExportTSV() failed: %s", err)
+	}
+	expectedTSV := strings.Replace(expectedCSV, ",", "\t", -1)
+	if tsv.String() != expectedTSV {
+		t.Fatalf("ExportTSV() wrote %q, expected %q", tsv.String(), expectedTSV)
+	}
+}
+
+func TestParseOSUHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		expectErr bool
+		expected  map[string]string
+	}{
+		{
+			name:   "latency",
+			output: osuLatencyOutput,
+			expected: map[string]string{
+				"title":   "OSU MPI Latency Test",
+				"version": "5.8",
+				"units":   "us",
+			},
+		},
+		{
+			name: "datatype",
+			output: `# OSU MPI Latency Test v7.0
+# Datatype: MPI_CHAR.
+# Size       Avg Latency(us)
+1                       1.50
+`,
+			expected: map[string]string{
+				"title":    "OSU MPI Latency Test",
+				"version":  "7.0",
+				"datatype": "MPI_CHAR.",
+				"units":    "us",
+			},
+		},
+		{
+			name: "multiple bandwidth and message rate",
+			output: `# OSU MPI Multiple Bandwidth / Message Rate Test v5.8
+# [ pairs: 1 ] [ window size: 64 ]
+# Size                  MB/s        Messages/s
+1                       2.93        2930000.20
+`,
+			expected: map[string]string{
+				"title":       "OSU MPI Multiple Bandwidth / Message Rate Test",
+				"version":     "5.8",
+				"pairs":       "1",
+				"window size": "64",
+				"units":       "MB/s",
+			},
+		},
+		{
+			name: "line that is not a key and a value",
+			output: `# OSU MPI Latency Test v5.8
+# Warning (at rank 0): unexpected value
+# Size          Latency (us)
+`,
+			expected: map[string]string{
+				"title":   "OSU MPI Latency Test",
+				"version": "5.8",
+				"units":   "us",
+			},
+		},
+		{
+			name:      "no header",
+			output:    "1 1.5\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := ParseOSUHeader(strings.NewReader(tt.output))
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ParseOSUHeader() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOSUHeader() failed: %s", err)
+			}
+			if !reflect.DeepEqual(details, tt.expected) {
+				t.Fatalf("ParseOSUHeader() returned %v, expected %v", details, tt.expected)
+			}
+		})
+	}
+}
+
+func writeGzipFile(t *testing.T, path string, content string) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	_, err := gzw.Write([]byte(content))
+	if err != nil {
+		t.Fatalf("unable to compress %s: %s", path, err)
+	}
+	err = gzw.Close()
+	if err != nil {
+		t.Fatalf("unable to compress %s: %s", path, err)
+	}
+	err = ioutil.WriteFile(path, buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", path, err)
+	}
+}
+
+func TestParseOSUOutputFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	plainPath := filepath.Join(tempDir, "osu_latency.out")
+	err = ioutil.WriteFile(plainPath, []byte(osuLatencyOutput), 0644)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", plainPath, err)
+	}
+	gzipPath := filepath.Join(tempDir, "osu_latency.out.gz")
+	writeGzipFile(t, gzipPath, osuLatencyOutput)
+	// Compressed content is detected even without the extension
+	unnamedGzipPath := filepath.Join(tempDir, "osu_latency.compressed")
+	writeGzipFile(t, unnamedGzipPath, osuLatencyOutput)
+	invalidGzipPath := filepath.Join(tempDir, "invalid.out.gz")
+	err = ioutil.WriteFile(invalidGzipPath, []byte(osuLatencyOutput), 0644)
+	if err != nil {
+		t.Fatalf("unable to create %s: %s", invalidGzipPath, err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		expectErr bool
+	}{
+		{name: "plain text", path: plainPath},
+		{name: "gzipped", path: gzipPath},
+		{name: "gzipped without extension", path: unnamedGzipPath},
+		{name: "invalid gzip", path: invalidGzipPath, expectErr: true},
+		{name: "missing file", path: filepath.Join(tempDir, "missing.out"), expectErr: true},
+	}
+
+	expected := []*DataPoint{{Size: 0, Value: 1.5}, {Size: 1, Value: 1.6}, {Size: 2, Value: 1.7}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseOSUOutputFile(tt.path)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ParseOSUOutputFile() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOSUOutputFile() failed: %s", err)
+			}
+			if !reflect.DeepEqual(result.DataPoints, expected) {
+				t.Fatalf("ParseOSUOutputFile() returned %v, expected %v", result.DataPoints, expected)
+			}
+		})
+	}
+}
+
+func TestParseOSUOutputFiles(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go_benchmark-")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	outputs := []string{osuLatencyOutput, osuBandwidthOutput, osuBiBandwidthOutput, osuAllreduceFullOutput}
+	var paths []string
+	var expected []*Result
+	for i, output := range outputs {
+		path := filepath.Join(tempDir, fmt.Sprintf("output%d.out", i))
+		err = ioutil.WriteFile(path, []byte(output), 0644)
+		if err != nil {
+			t.Fatalf("unable to create %s: %s", path, err)
+		}
+		paths = append(paths, path)
+		result, _, err := ParseOSUOutput(strings.NewReader(output))
+		if err != nil {
+			t.Fatalf("ParseOSUOutput() failed: %s", err)
+		}
+		expected = append(expected, result)
+	}
+
+	for _, workers := range []int{1, 2, len(paths) + 1} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results, err := ParseOSUOutputFiles(paths, workers)
+			if err != nil {
+				t.Fatalf("ParseOSUOutputFiles() failed: %s", err)
+			}
+			// Results are in the same order as the files, whatever the order of completion
+			if !reflect.DeepEqual(results.Result, expected) {
+				t.Fatalf("ParseOSUOutputFiles() returned unexpected results")
+			}
+		})
+	}
+
+	_, err = ParseOSUOutputFiles(append(paths, filepath.Join(tempDir, "missing.out")), 2)
+	if err == nil {
+		t.Fatalf("ParseOSUOutputFiles() succeeded with a missing file, expected an error")
+	}
+	_, err = ParseOSUOutputFiles(paths, 0)
+	if err == nil {
+		t.Fatalf("ParseOSUOutputFiles() succeeded without workers, expected an error")
+	}
+}
@@ -0,0 +1,317 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PlotOptions gathers the options used to plot data
+type PlotOptions struct {
+	// Title is the title of the plot
+	Title string
+
+	// XLabel is the label of the X axis, i.e., the message size
+	XLabel string
+
+	// YLabel is the label of the Y axis, i.e., the values
+	YLabel string
+
+	// LogX specifies whether the X axis uses a logarithmic scale
+	LogX bool
+
+	// LogY specifies whether the Y axis uses a logarithmic scale
+	LogY bool
+}
+
+const (
+	// LineChart is the type of charts where the points of a series are connected by lines
+	LineChart = "line"
+
+	// ScatterChart is the type of charts where the points of a series are not connected
+	ScatterChart = "scatter"
+)
+
+const (
+	plotWidth        = 800
+	plotHeight       = 500
+	plotMarginLeft   = 80
+	plotMarginRight  = 180
+	plotMarginTop    = 50
+	plotMarginBottom = 60
+	plotNumTicks     = 5
+)
+
+// plotColors is the palette used for the series of a plot
+var plotColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// seriesColors returns the colors to use for the series of a plot
+func seriesColors(spreadsheetData *SpreadsheetData) ([]string, error) {
+	if len(spreadsheetData.SeriesColors) == 0 {
+		return plotColors, nil
+	}
+
+	var colors []string
+	for _, color := range spreadsheetData.SeriesColors {
+		hexColor := strings.TrimPrefix(color, "#")
+		_, err := hex.DecodeString(hexColor)
+		if len(hexColor) != 6 || err != nil {
+			return nil, fmt.Errorf("invalid color %q, must be a hexadecimal color such as #1f77b4", color)
+		}
+		colors = append(colors, "#"+strings.ToLower(hexColor))
+	}
+	return colors, nil
+}
+
+// plotSeries is a series of points to plot
+type plotSeries struct {
+	label  string
+	points []*DataPoint
+	// errors are the standard deviations of the points, 0 when a point does not have an error bar
+	errors []float64
+}
+
+// plotAxis maps values to a position on an axis
+type plotAxis struct {
+	min float64
+	max float64
+	log bool
+}
+
+func newPlotAxis(values []float64, log bool) plotAxis {
+	a := plotAxis{min: math.Inf(1), max: math.Inf(-1), log: log}
+	for _, v := range values {
+		a.min = math.Min(a.min, v)
+		a.max = math.Max(a.max, v)
+	}
+	if log {
+		// Axes with a logarithmic scale cover full decades
+		a.min = math.Pow(10, math.Floor(math.Log10(a.min)))
+		a.max = math.Pow(10, math.Ceil(math.Log10(a.max)))
+	}
+	if a.min == a.max {
+		if log {
+			a.max *= 10
+		} else {
+			a.min--
+			a.max++
+		}
+	}
+	return a
+}
+
+// position returns the relative position of a value on the axis, between 0 and 1
+func (a plotAxis) position(v float64) float64 {
+	if a.log {
+		return (math.Log10(v) - math.Log10(a.min)) / (math.Log10(a.max) - math.Log10(a.min))
+	}
+	return (v - a.min) / (a.max - a.min)
+}
+
+func (a plotAxis) ticks() []float64 {
+	var ticks []float64
+	if a.log {
+		minExp := int(math.Round(math.Log10(a.min)))
+		maxExp := int(math.Round(math.Log10(a.max)))
+		for e := minExp; e <= maxExp; e++ {
+			ticks = append(ticks, math.Pow(10, float64(e)))
+		}
+		return ticks
+	}
+	step := (a.max - a.min) / plotNumTicks
+	for i := 0; i <= plotNumTicks; i++ {
+		ticks = append(ticks, a.min+float64(i)*step)
+	}
+	return ticks
+}
+
+// errorBarsBySize returns, for each result, the standard deviations of the error bars indexed by size;
+// the map of a result without error bars is nil
+func errorBarsBySize(spreadsheetData *SpreadsheetData) ([]map[float64]float64, error) {
+	errorBars := make([]map[float64]float64, len(spreadsheetData.Data.Result))
+	if spreadsheetData.ErrorBars == nil {
+		return errorBars, nil
+	}
+	if len(spreadsheetData.ErrorBars.Result) != len(spreadsheetData.Data.Result) {
+		return nil, fmt.Errorf("%d error bar results for %d results", len(spreadsheetData.ErrorBars.Result), len(spreadsheetData.Data.Result))
+	}
+	for i, r := range spreadsheetData.ErrorBars.Result {
+		if r == nil {
+			continue
+		}
+		errorBars[i] = make(map[float64]float64)
+		for j, dp := range r.DataPoints {
+			if dp == nil {
+				return nil, fmt.Errorf("undefined data point %d in the error bars of result %d", j, i)
+			}
+			if dp.Value < 0 {
+				return nil, fmt.Errorf("negative standard deviation %f for size %f of result %d", dp.Value, dp.Size, i)
+			}
+			if _, ok := errorBars[i][dp.Size]; ok {
+				return nil, fmt.Errorf("duplicate size %f in the error bars of result %d", dp.Size, i)
+			}
+			errorBars[i][dp.Size] = dp.Value
+		}
+	}
+	return errorBars, nil
+}
+
+// getPlotSeries returns one series per result; points with a value that is not finite or that cannot be
+// represented with a logarithmic scale are ignored, as well as error bars that are not finite
+func getPlotSeries(spreadsheetData *SpreadsheetData, logX, logY bool) ([]*plotSeries, error) {
+	t, err := newDataTable(spreadsheetData)
+	if err != nil {
+		return nil, err
+	}
+	errorBars, err := errorBarsBySize(spreadsheetData)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []*plotSeries
+	numPoints := 0
+	for col, label := range t.labels {
+		s := &plotSeries{label: label}
+		for row, size := range t.sizes {
+			cell := t.cells[row][col]
+			if cell == nil || math.IsNaN(*cell) || math.IsInf(*cell, 0) || (logX && size <= 0) || (logY && *cell <= 0) {
+				continue
+			}
+			stddev := errorBars[col][size]
+			if math.IsNaN(stddev) || math.IsInf(stddev, 0) {
+				stddev = 0
+			}
+			s.points = append(s.points, &DataPoint{Size: size, Value: *cell})
+			s.errors = append(s.errors, stddev)
+		}
+		numPoints += len(s.points)
+		series = append(series, s)
+	}
+	if numPoints == 0 {
+		return nil, fmt.Errorf("no data point to plot")
+	}
+	return series, nil
+}
+
+func formatTick(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// PlotResultsSVG plots the data as a SVG image, with one series per result and the message size on the X axis.
+// When the data has error bars, they span one standard deviation around each point; on a logarithmic Y axis,
+// the lower part of the bars is clamped to the bottom of the axis when it is not positive.
+func PlotResultsSVG(w io.Writer, spreadsheetData *SpreadsheetData, opts PlotOptions) error {
+	series, err := getPlotSeries(spreadsheetData, opts.LogX, opts.LogY)
+	if err != nil {
+		return fmt.Errorf("unable to plot data: %w", err)
+	}
+	colors, err := seriesColors(spreadsheetData)
+	if err != nil {
+		return fmt.Errorf("unable to plot data: %w", err)
+	}
+	chartType := spreadsheetData.ChartType
+	if chartType == "" {
+		chartType = LineChart
+	}
+	if chartType != LineChart && chartType != ScatterChart {
+		return fmt.Errorf("unable to plot data: unsupported chart type %q", chartType)
+	}
+
+	var sizes, values []float64
+	for _, s := range series {
+		for i, dp := range s.points {
+			sizes = append(sizes, dp.Size)
+			values = append(values, dp.Value)
+			if s.errors[i] > 0 {
+				values = append(values, dp.Value+s.errors[i])
+				if !opts.LogY || dp.Value-s.errors[i] > 0 {
+					values = append(values, dp.Value-s.errors[i])
+				}
+			}
+		}
+	}
+	xAxis := newPlotAxis(sizes, opts.LogX)
+	yAxis := newPlotAxis(values, opts.LogY)
+	areaWidth := float64(plotWidth - plotMarginLeft - plotMarginRight)
+	areaHeight := float64(plotHeight - plotMarginTop - plotMarginBottom)
+	x := func(v float64) float64 { return plotMarginLeft + xAxis.position(v)*areaWidth }
+	y := func(v float64) float64 { return plotMarginTop + (1-yAxis.position(v))*areaHeight }
+	left := float64(plotMarginLeft)
+	right := left + areaWidth
+	top := float64(plotMarginTop)
+	bottom := top + areaHeight
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"12\">\n", plotWidth, plotHeight, plotWidth, plotHeight)
+	fmt.Fprintf(&svg, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", plotWidth, plotHeight)
+	if opts.Title != "" {
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\" font-size=\"16\">%s</text>\n", left+areaWidth/2, plotMarginTop/2, html.EscapeString(opts.Title))
+	}
+
+	// Grid and ticks
+	for _, tick := range xAxis.ticks() {
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#dddddd\"/>\n", x(tick), top, x(tick), bottom)
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%s</text>\n", x(tick), bottom+16, formatTick(tick))
+	}
+	for _, tick := range yAxis.ticks() {
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#dddddd\"/>\n", left, y(tick), right, y(tick))
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%s</text>\n", left-6, y(tick)+4, formatTick(tick))
+	}
+
+	// Axes and their labels
+	fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\"/>\n", left, bottom, right, bottom)
+	fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\"/>\n", left, top, left, bottom)
+	if opts.XLabel != "" {
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%s</text>\n", left+areaWidth/2, bottom+40, html.EscapeString(opts.XLabel))
+	}
+	if opts.YLabel != "" {
+		fmt.Fprintf(&svg, "<text transform=\"translate(20 %.1f) rotate(-90)\" text-anchor=\"middle\">%s</text>\n", top+areaHeight/2, html.EscapeString(opts.YLabel))
+	}
+
+	// Series and legend
+	for i, s := range series {
+		color := colors[i%len(colors)]
+		var points []string
+		for _, dp := range s.points {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(dp.Size), y(dp.Value)))
+		}
+		if chartType == LineChart {
+			fmt.Fprintf(&svg, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"2\"/>\n", strings.Join(points, " "), color)
+		}
+		for j, dp := range s.points {
+			if s.errors[j] == 0 {
+				continue
+			}
+			low := dp.Value - s.errors[j]
+			if opts.LogY && low < yAxis.min {
+				low = yAxis.min
+			}
+			high := dp.Value + s.errors[j]
+			fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\"/>\n", x(dp.Size), y(low), x(dp.Size), y(high), color)
+			for _, v := range []float64{low, high} {
+				fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\"/>\n", x(dp.Size)-4, y(v), x(dp.Size)+4, y(v), color)
+			}
+		}
+		for _, dp := range s.points {
+			fmt.Fprintf(&svg, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"3\" fill=\"%s\"/>\n", x(dp.Size), y(dp.Value), color)
+		}
+
+		legendY := top + float64(i)*20
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"2\"/>\n", right+20, legendY, right+40, legendY, color)
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\">%s</text>\n", right+46, legendY+4, html.EscapeString(s.label))
+	}
+	svg.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, svg.String())
+	return err
+}
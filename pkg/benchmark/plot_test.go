@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPlotResultsSVGErrorBars(t *testing.T) {
+	data := &Results{Result: []*Result{
+		{DataPoints: []*DataPoint{{Size: 1, Value: 10}, {Size: 2, Value: 20}}},
+		{DataPoints: []*DataPoint{{Size: 1, Value: 5}}},
+	}}
+
+	tests := []struct {
+		name             string
+		errorBars        *Results
+		logY             bool
+		expectErr        bool
+		expectedBarLines int
+		expectedTick     string
+	}{
+		{
+			name:             "no error bars",
+			expectedBarLines: 0,
+		},
+		{
+			name: "error bars of one series",
+			errorBars: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{{Size: 2, Value: 30}, {Size: 1, Value: 0}}},
+				nil,
+			}},
+			expectedBarLines: 3,
+			// The axis includes the top of the error bar, i.e., 50
+			expectedTick: ">50<",
+		},
+		{
+			name: "error bars below zero on a logarithmic axis",
+			errorBars: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{{Size: 1, Value: 20}}},
+				{DataPoints: []*DataPoint{{Size: 1, Value: 1}}},
+			}},
+			logY:             true,
+			expectedBarLines: 6,
+			expectedTick:     ">1<",
+		},
+		{
+			name:      "missing error bars",
+			errorBars: &Results{Result: []*Result{nil}},
+			expectErr: true,
+		},
+		{
+			name: "undefined standard deviation",
+			errorBars: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{nil}},
+				nil,
+			}},
+			expectErr: true,
+		},
+		{
+			name: "negative standard deviation",
+			errorBars: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{{Size: 1, Value: -1}}},
+				nil,
+			}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spreadsheetData := &SpreadsheetData{
+				Labels:    []string{"a", "b"},
+				Data:      data,
+				ErrorBars: tt.errorBars,
+			}
+			var buf bytes.Buffer
+			err := PlotResultsSVG(&buf, spreadsheetData, PlotOptions{LogY: tt.logY})
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("PlotResultsSVG() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlotResultsSVG() failed: %s", err)
+			}
+			svg := buf.String()
+			if strings.Contains(svg, "NaN") || strings.Contains(svg, "Inf") {
+				t.Fatalf("PlotResultsSVG() wrote invalid coordinates: %s", svg)
+			}
+			// Lines of the grid, of the axes and of the legend have a specific color or width
+			barLines := 0
+			for _, line := range strings.Split(svg, "\n") {
+				if strings.HasPrefix(line, "<line") && !strings.Contains(line, "#dddddd") && !strings.Contains(line, "black") && !strings.Contains(line, "stroke-width") {
+					barLines++
+				}
+			}
+			if barLines != tt.expectedBarLines {
+				t.Fatalf("PlotResultsSVG() drew %d error bar lines, expected %d", barLines, tt.expectedBarLines)
+			}
+			if tt.expectedTick != "" && !strings.Contains(svg, tt.expectedTick) {
+				t.Fatalf("PlotResultsSVG() did not write the %s tick", tt.expectedTick)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import "fmt"
+
+// checkSameSizes makes sure all the results have the same sizes, in the same order
+func (r *Results) checkSameSizes() error {
+	if len(r.Result) == 0 {
+		return fmt.Errorf("no result")
+	}
+	err := r.ValidateUniformLength()
+	if err != nil {
+		return err
+	}
+
+	reference := r.Result[0].DataPoints
+	for i, result := range r.Result[1:] {
+		for j, dp := range result.DataPoints {
+			if dp.Size != reference[j].Size {
+				return fmt.Errorf("result %d has size %f at index %d while result 0 has size %f", i+1, dp.Size, j, reference[j].Size)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that the data points are sorted by ascending size and that sizes are unique
+func (r *Result) Validate() error {
+	for i := 1; i < len(r.DataPoints); i++ {
+		if r.DataPoints[i].Size == r.DataPoints[i-1].Size {
+			return fmt.Errorf("duplicate size %f at index %d", r.DataPoints[i].Size, i)
+		}
+		if r.DataPoints[i].Size < r.DataPoints[i-1].Size {
+			return fmt.Errorf("size %f at index %d is smaller than the previous size %f", r.DataPoints[i].Size, i, r.DataPoints[i-1].Size)
+		}
+	}
+	return nil
+}
+
+// MergeResults concatenates multiple sets of results into a single one. All the results must have the same
+// sizes, in the same order, so that the merged data lines up in a spreadsheet.
+func MergeResults(resultsList ...*Results) (*Results, error) {
+	merged := new(Results)
+	for i, results := range resultsList {
+		if results == nil {
+			return nil, fmt.Errorf("results %d are undefined", i)
+		}
+		merged.Result = append(merged.Result, results.Result...)
+	}
+
+	err := merged.checkSameSizes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge results: %w", err)
+	}
+	return merged, nil
+}
+
+// Sizes returns the message sizes shared by all the results
+func (r *Results) Sizes() ([]float64, error) {
+	err := r.checkSameSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make([]float64, len(r.Result[0].DataPoints))
+	for i, dp := range r.Result[0].DataPoints {
+		sizes[i] = dp.Size
+	}
+	return sizes, nil
+}
+
+// RemoveIterations returns a new slice of iterations without the iterations at the given indices;
+// the slice of iterations is not modified
+func RemoveIterations(iterations []*Result, indices []int) ([]*Result, error) {
+	toRemove := make(map[int]bool)
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(iterations) {
+			return nil, fmt.Errorf("invalid iteration index %d, must be between 0 and %d", idx, len(iterations)-1)
+		}
+		toRemove[idx] = true
+	}
+
+	var kept []*Result
+	for i, iteration := range iterations {
+		if !toRemove[i] {
+			kept = append(kept, iteration)
+		}
+	}
+	return kept, nil
+}
+
+func (d *DataPoint) clone() *DataPoint {
+	clone := &DataPoint{Size: d.Size, Value: d.Value}
+	if d.Extra != nil {
+		clone.Extra = make(map[string]float64, len(d.Extra))
+		for k, v := range d.Extra {
+			clone.Extra[k] = v
+		}
+	}
+	return clone
+}
+
+// Clone returns a deep copy of the result
+func (r *Result) Clone() *Result {
+	clone := new(Result)
+	if r.DataPoints != nil {
+		clone.DataPoints = make([]*DataPoint, len(r.DataPoints))
+	}
+	for i, dp := range r.DataPoints {
+		if dp != nil {
+			clone.DataPoints[i] = dp.clone()
+		}
+	}
+	return clone
+}
+
+// Clone returns a deep copy of the results
+func (r *Results) Clone() *Results {
+	clone := new(Results)
+	if r.Result != nil {
+		clone.Result = make([]*Result, len(r.Result))
+	}
+	for i, result := range r.Result {
+		if result != nil {
+			clone.Result[i] = result.Clone()
+		}
+	}
+	return clone
+}
+
+// ForEachPoint calls fn for each data point of each result, with the index of the result the data point belongs to
+func (r *Results) ForEachPoint(fn func(resultIdx int, dp *DataPoint)) {
+	for i, result := range r.Result {
+		if result == nil {
+			continue
+		}
+		for _, dp := range result.DataPoints {
+			fn(i, dp)
+		}
+	}
+}
+
+// SizeRange returns the smallest and largest sizes across all the results
+func (r *Results) SizeRange() (min, max float64, err error) {
+	numPoints := 0
+	r.ForEachPoint(func(_ int, dp *DataPoint) {
+		if numPoints == 0 || dp.Size < min {
+			min = dp.Size
+		}
+		if numPoints == 0 || dp.Size > max {
+			max = dp.Size
+		}
+		numPoints++
+	})
+	if numPoints == 0 {
+		return 0, 0, fmt.Errorf("no data point")
+	}
+	return min, max, nil
+}
+
+// ValidateUniformLength checks that all the results have the same number of data points
+func (r *Results) ValidateUniformLength() error {
+	if len(r.Result) == 0 {
+		return nil
+	}
+	for i, result := range r.Result {
+		if result == nil {
+			return fmt.Errorf("result %d is undefined", i)
+		}
+		if len(result.DataPoints) != len(r.Result[0].DataPoints) {
+			return fmt.Errorf("result %d has %d data points while result 0 has %d", i, len(result.DataPoints), len(r.Result[0].DataPoints))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResultsClone(t *testing.T) {
+	tests := []struct {
+		name    string
+		results *Results
+	}{
+		{
+			name:    "no results",
+			results: &Results{},
+		},
+		{
+			name:    "empty result",
+			results: &Results{Result: []*Result{{DataPoints: []*DataPoint{}}}},
+		},
+		{
+			name: "undefined result and data point",
+			results: &Results{Result: []*Result{
+				nil,
+				{DataPoints: []*DataPoint{nil, {Size: 1, Value: 2}}},
+			}},
+		},
+		{
+			name: "data points with extra values",
+			results: &Results{Result: []*Result{
+				{DataPoints: []*DataPoint{
+					{Size: 1, Value: 2, Extra: map[string]float64{ExtraAvg: 2, ExtraMin: 1, ExtraMax: 3}},
+					{Size: 2, Value: 4},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone := tt.results.Clone()
+			if !reflect.DeepEqual(clone, tt.results) {
+				t.Fatalf("Clone() returned a different copy")
+			}
+
+			// Modifying the copy must not modify the original results
+			original := tt.results.Clone()
+			for _, result := range clone.Result {
+				if result == nil {
+					continue
+				}
+				for _, dp := range result.DataPoints {
+					if dp == nil {
+						continue
+					}
+					dp.Size++
+					dp.Value++
+					for k := range dp.Extra {
+						dp.Extra[k]++
+					}
+					if dp.Extra != nil {
+						dp.Extra["new"] = 1
+					}
+				}
+				if len(result.DataPoints) > 0 {
+					result.DataPoints[0] = nil
+				}
+			}
+			if !reflect.DeepEqual(tt.results, original) {
+				t.Fatalf("modifying the copy modified the original results")
+			}
+		})
+	}
+}
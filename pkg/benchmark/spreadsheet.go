@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SpreadsheetData gathers the data to be exported into a spreadsheet
+type SpreadsheetData struct {
+	// Labels are the labels of the columns, one per result
+	Labels []string
+
+	// Data is the set of results to export, one column per result
+	Data *Results
+
+	// AllowMissingLabels specifies whether there can be fewer labels than results, in which case the
+	// columns of the results without a label have an empty label
+	AllowMissingLabels bool
+	// SeriesColors are the hexadecimal colors of the series in charts, e.g., "#1f77b4", one per label.
+	// Colors are reused when there are fewer colors than series.
+	SeriesColors []string
+	// ChartType is the type of charts: LineChart (default) or ScatterChart
+	ChartType string
+	// ErrorBars are the standard deviations drawn as error bars in charts, e.g., as returned by AggregateIterations,
+	// with one result per result of Data (nil for no error bars) and data points aligned by size. Optional.
+	ErrorBars *Results
+}
+
+// SpreadsheetMetadata gathers the metadata to be added to a spreadsheet
+type SpreadsheetMetadata struct {
+	// Timestamp is the time at which the data was generated, in the RFC3339 format
+	Timestamp string
+
+	// Content is the content of the metadata, one row per string (only one column for now)
+	Content []string
+}
+
+// NewSpreadsheetMetadata creates the metadata for a spreadsheet with a RFC3339 timestamp
+func NewSpreadsheetMetadata(t time.Time, content []string) *SpreadsheetMetadata {
+	return &SpreadsheetMetadata{
+		Timestamp: t.Format(time.RFC3339),
+		Content:   content,
+	}
+}
+
+// metadataBenchmark is the prefix of the metadata identifying the benchmark, see AddConfig
+const metadataBenchmark = "Benchmark: "
+
+// AddConfig records the benchmark that produced the data in the content of the metadata,
+// e.g., "Benchmark: osu-5.8", using the identifier returned by Config.String
+func (m *SpreadsheetMetadata) AddConfig(c *Config) {
+	m.Content = append(m.Content, metadataBenchmark+c.String())
+}
+
+// Time parses the timestamp of the metadata
+func (m *SpreadsheetMetadata) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, m.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", m.Timestamp, err)
+	}
+	return t, nil
+}
+
+// dataTable is a tabular view of a SpreadsheetData: the first column is the message size
+// and there is one column per result
+type dataTable struct {
+	labels []string
+	sizes  []float64
+	// cells[row][col] is nil when the result of the column does not have a data point for the size of the row
+	cells [][]*float64
+}
+
+func newDataTable(spreadsheetData *SpreadsheetData) (*dataTable, error) {
+	if spreadsheetData == nil || spreadsheetData.Data == nil {
+		return nil, fmt.Errorf("undefined data")
+	}
+	if len(spreadsheetData.Data.Result) == 0 {
+		return nil, fmt.Errorf("no result to export")
+	}
+
+	numLabels := len(spreadsheetData.Labels)
+	numResults := len(spreadsheetData.Data.Result)
+	if numLabels > numResults || (numLabels < numResults && !spreadsheetData.AllowMissingLabels) {
+		return nil, fmt.Errorf("%d labels for %d results", numLabels, numResults)
+	}
+	for i, r := range spreadsheetData.Data.Result {
+		if r == nil {
+			continue
+		}
+		// Rows are sorted by size so the data points can be in any order, but a size cannot appear twice
+		sizes := make(map[float64]bool)
+		for j, dp := range r.DataPoints {
+			if dp == nil {
+				return nil, fmt.Errorf("invalid result %d: undefined data point %d", i, j)
+			}
+			if sizes[dp.Size] {
+				return nil, fmt.Errorf("invalid result %d: duplicate size %f at index %d", i, dp.Size, j)
+			}
+			sizes[dp.Size] = true
+		}
+	}
+
+	t := new(dataTable)
+	rowIDs := make(map[float64]int)
+	for _, r := range spreadsheetData.Data.Result {
+		if r == nil {
+			continue
+		}
+		for _, dp := range r.DataPoints {
+			if _, ok := rowIDs[dp.Size]; !ok {
+				rowIDs[dp.Size] = 0
+				t.sizes = append(t.sizes, dp.Size)
+			}
+		}
+	}
+	sort.Float64s(t.sizes)
+	for i, size := range t.sizes {
+		rowIDs[size] = i
+	}
+
+	numCols := len(spreadsheetData.Data.Result)
+	t.labels = make([]string, numCols)
+	copy(t.labels, spreadsheetData.Labels)
+	t.cells = make([][]*float64, len(t.sizes))
+	for i := range t.cells {
+		t.cells[i] = make([]*float64, numCols)
+	}
+	for col, r := range spreadsheetData.Data.Result {
+		if r == nil {
+			continue
+		}
+		for _, dp := range r.DataPoints {
+			value := dp.Value
+			t.cells[rowIDs[dp.Size]][col] = &value
+		}
+	}
+
+	return t, nil
+}
+
+// ColumnIndex converts the name of a spreadsheet column, e.g., "AB", to a 0-based index
+func ColumnIndex(col string) (int, error) {
+	if col == "" {
+		return 0, fmt.Errorf("empty column name")
+	}
+	idx := 0
+	for _, c := range strings.ToUpper(col) {
+		if c < 'A' || c > 'Z' {
+			return 0, fmt.Errorf("invalid column name %q", col)
+		}
+		idx = idx*26 + int(c-'A') + 1
+	}
+	return idx - 1, nil
+}
+
+// ColumnName converts a 0-based column index to the name of the spreadsheet column, e.g., 27 to "AB";
+// the name of a negative index is empty
+func ColumnName(idx int) string {
+	name := ""
+	for n := idx + 1; n > 0; n = (n - 1) / 26 {
+		name = string(rune('A'+(n-1)%26)) + name
+	}
+	return name
+}
+
+// SortedDatasetKeys returns the keys of a set of datasets in sorted order, so that datasets can be processed
+// in a reproducible order
+func SortedDatasetKeys(datasets map[string]*SpreadsheetData) []string {
+	keys := make([]string, 0, len(datasets))
+	for key := range datasets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,43 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSpreadsheetMetadataAddConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		expected string
+	}{
+		{
+			name:     "name and version",
+			config:   &Config{Name: "osu", Version: "5.8"},
+			expected: "Benchmark: osu-5.8",
+		},
+		{
+			name:     "no version",
+			config:   &Config{Name: "osu"},
+			expected: "Benchmark: osu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := NewSpreadsheetMetadata(time.Now(), []string{"Run 1"})
+			metadata.AddConfig(tt.config)
+			expected := []string{"Run 1", tt.expected}
+			if !reflect.DeepEqual(metadata.Content, expected) {
+				t.Fatalf("AddConfig() resulted in %v, expected %v", metadata.Content, expected)
+			}
+		})
+	}
+}
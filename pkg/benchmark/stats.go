@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Statistics computes, for each size, the mean and the sample standard deviation of the values across all the results.
+// All the results must have the same sizes, in the same order.
+func (r *Results) Statistics() ([]*DataPoint, []*DataPoint, error) {
+	err := r.checkSameSizes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to compute statistics: %w", err)
+	}
+
+	n := float64(len(r.Result))
+	var means []*DataPoint
+	var stddevs []*DataPoint
+	for i, dp := range r.Result[0].DataPoints {
+		sum := 0.0
+		for _, result := range r.Result {
+			sum += result.DataPoints[i].Value
+		}
+		mean := sum / n
+
+		stddev := 0.0
+		if len(r.Result) > 1 {
+			sumSquares := 0.0
+			for _, result := range r.Result {
+				delta := result.DataPoints[i].Value - mean
+				sumSquares += delta * delta
+			}
+			stddev = math.Sqrt(sumSquares / (n - 1))
+		}
+
+		means = append(means, &DataPoint{Size: dp.Size, Value: mean})
+		stddevs = append(stddevs, &DataPoint{Size: dp.Size, Value: stddev})
+	}
+	return means, stddevs, nil
+}
+
+// Min returns the data point with the smallest value
+func (r *Result) Min() (*DataPoint, error) {
+	if len(r.DataPoints) == 0 {
+		return nil, fmt.Errorf("empty result")
+	}
+	min := r.DataPoints[0]
+	for _, dp := range r.DataPoints[1:] {
+		if dp.Value < min.Value {
+			min = dp
+		}
+	}
+	return min, nil
+}
+
+// Max returns the data point with the largest value
+func (r *Result) Max() (*DataPoint, error) {
+	if len(r.DataPoints) == 0 {
+		return nil, fmt.Errorf("empty result")
+	}
+	max := r.DataPoints[0]
+	for _, dp := range r.DataPoints[1:] {
+		if dp.Value > max.Value {
+			max = dp
+		}
+	}
+	return max, nil
+}
+
+// sortedValues returns the values of the result sorted in ascending order
+func (r *Result) sortedValues() []float64 {
+	values := make([]float64, len(r.DataPoints))
+	for i, dp := range r.DataPoints {
+		values[i] = dp.Value
+	}
+	sort.Float64s(values)
+	return values
+}
+
+// Median returns the median of the values
+func (r *Result) Median() (float64, error) {
+	if len(r.DataPoints) == 0 {
+		return 0, fmt.Errorf("empty result")
+	}
+	values := r.sortedValues()
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2], nil
+	}
+	return (values[n/2-1] + values[n/2]) / 2, nil
+}
+
+// Percentile returns the p-th percentile (0 to 100) of the values, using linear interpolation between ranks
+func (r *Result) Percentile(p float64) (float64, error) {
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, fmt.Errorf("invalid percentile %f, must be between 0 and 100", p)
+	}
+	if len(r.DataPoints) == 0 {
+		return 0, fmt.Errorf("empty result")
+	}
+
+	values := r.sortedValues()
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	if lower == len(values)-1 {
+		return values[lower], nil
+	}
+	fraction := rank - float64(lower)
+	return values[lower] + fraction*(values[lower+1]-values[lower]), nil
+}
+
+// AggregateIterations aggregates the results of repeated iterations of a benchmark into the mean and the sample
+// standard deviation for each size. All the iterations must have the same sizes, in the same order.
+func AggregateIterations(iterations []*Result) (mean *Result, stddev *Result, err error) {
+	results := &Results{Result: iterations}
+	means, stddevs, err := results.Statistics()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Result{DataPoints: means}, &Result{DataPoints: stddevs}, nil
+}
+
+// DetectOutliers flags, for each size, the iterations which value is more than zThreshold standard deviations
+// away from the mean of the other iterations. Each iteration is compared to the mean and the sample standard
+// deviation of the other iterations, since an outlier would otherwise inflate the standard deviation it is
+// compared to; when the other iterations all have the same value, any different value is an outlier.
+// At least 3 iterations are required and all the iterations must have the same sizes, in the same order.
+// The returned map is indexed by size and only includes the sizes with outliers.
+func DetectOutliers(iterations []*Result, zThreshold float64) (map[float64][]int, error) {
+	if zThreshold <= 0 {
+		return nil, fmt.Errorf("invalid threshold %f, must be positive", zThreshold)
+	}
+	if len(iterations) < 3 {
+		return nil, fmt.Errorf("at least 3 iterations are required to detect outliers, got %d", len(iterations))
+	}
+	results := &Results{Result: iterations}
+	err := results.checkSameSizes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to detect outliers: %w", err)
+	}
+
+	n := float64(len(iterations) - 1)
+	outliers := make(map[float64][]int)
+	for i, dp := range iterations[0].DataPoints {
+		for iterationID, iteration := range iterations {
+			sum := 0.0
+			for otherID, other := range iterations {
+				if otherID != iterationID {
+					sum += other.DataPoints[i].Value
+				}
+			}
+			mean := sum / n
+
+			sumSquares := 0.0
+			for otherID, other := range iterations {
+				if otherID != iterationID {
+					delta := other.DataPoints[i].Value - mean
+					sumSquares += delta * delta
+				}
+			}
+			stddev := math.Sqrt(sumSquares / (n - 1))
+
+			delta := math.Abs(iteration.DataPoints[i].Value - mean)
+			if (stddev == 0 && delta > 0) || (stddev > 0 && delta/stddev > zThreshold) {
+				outliers[dp.Size] = append(outliers[dp.Size], iterationID)
+			}
+		}
+	}
+	return outliers, nil
+}
+
+// GeoMean returns the geometric mean of the values, which must all be positive
+func (r *Result) GeoMean() (float64, error) {
+	if len(r.DataPoints) == 0 {
+		return 0, fmt.Errorf("empty result")
+	}
+	sumLogs := 0.0
+	for _, dp := range r.DataPoints {
+		if dp.Value <= 0 {
+			return 0, fmt.Errorf("value for size %f is not positive: %f", dp.Size, dp.Value)
+		}
+		sumLogs += math.Log(dp.Value)
+	}
+	return math.Exp(sumLogs / float64(len(r.DataPoints))), nil
+}
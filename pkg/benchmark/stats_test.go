@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func iterationsFromValues(values ...float64) []*Result {
+	var iterations []*Result
+	for _, value := range values {
+		iterations = append(iterations, &Result{DataPoints: []*DataPoint{{Size: 8, Value: value}}})
+	}
+	return iterations
+}
+
+func TestDetectOutliers(t *testing.T) {
+	tests := []struct {
+		name       string
+		iterations []*Result
+		zThreshold float64
+		expectErr  bool
+		expected   map[float64][]int
+	}{
+		{
+			name:       "single outlier among few iterations",
+			iterations: iterationsFromValues(10, 10.1, 9.9, 10, 50),
+			zThreshold: 3,
+			expected:   map[float64][]int{8: {4}},
+		},
+		{
+			name:       "outlier among identical values",
+			iterations: iterationsFromValues(10, 10, 10, 12),
+			zThreshold: 3,
+			expected:   map[float64][]int{8: {3}},
+		},
+		{
+			name:       "no outlier",
+			iterations: iterationsFromValues(10, 11, 9, 10.5),
+			zThreshold: 3,
+			expected:   map[float64][]int{},
+		},
+		{
+			name:       "identical values",
+			iterations: iterationsFromValues(10, 10, 10),
+			zThreshold: 3,
+			expected:   map[float64][]int{},
+		},
+		{
+			name:       "too few iterations",
+			iterations: iterationsFromValues(10, 50),
+			zThreshold: 3,
+			expectErr:  true,
+		},
+		{
+			name:       "invalid threshold",
+			iterations: iterationsFromValues(10, 10, 10),
+			zThreshold: 0,
+			expectErr:  true,
+		},
+		{
+			name: "different sizes",
+			iterations: append(iterationsFromValues(10, 10),
+				&Result{DataPoints: []*DataPoint{{Size: 16, Value: 10}}}),
+			zThreshold: 3,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outliers, err := DetectOutliers(tt.iterations, tt.zThreshold)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("DetectOutliers() succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectOutliers() failed: %s", err)
+			}
+			if !reflect.DeepEqual(outliers, tt.expected) {
+				t.Fatalf("DetectOutliers() returned %v, expected %v", outliers, tt.expected)
+			}
+		})
+	}
+}
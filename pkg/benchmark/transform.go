@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FilterBySizeRange returns a new result with only the data points which size is in [min, max];
+// the order of the data points is preserved and the result is not modified
+func (r *Result) FilterBySizeRange(min, max float64) *Result {
+	return r.Filter(func(dp *DataPoint) bool {
+		return dp.Size >= min && dp.Size <= max
+	})
+}
+
+// Downsample returns a new result with approximately target data points, selected with a uniform stride.
+// The first and last data points are always kept and the result is not modified.
+func (r *Result) Downsample(target int) (*Result, error) {
+	if target < 2 {
+		return nil, fmt.Errorf("invalid target %d, must be at least 2", target)
+	}
+
+	n := len(r.DataPoints)
+	if n <= target {
+		return r.Clone(), nil
+	}
+
+	downsampled := new(Result)
+	stride := float64(n-1) / float64(target-1)
+	for i := 0; i < target; i++ {
+		dp := r.DataPoints[int(math.Round(float64(i)*stride))]
+		downsampled.DataPoints = append(downsampled.DataPoints, dp.clone())
+	}
+	return downsampled, nil
+}
+
+// interpolateAt linearly interpolates the values of the result at the given sizes. Sizes outside of the
+// measured range get the value of the closest measured size when clamp is true, otherwise they are an error.
+// Sizes that are NaN or infinite are always an error.
+func (r *Result) interpolateAt(sizes []float64, clamp bool) (*Result, error) {
+	if len(r.DataPoints) == 0 {
+		return nil, fmt.Errorf("empty result")
+	}
+	sorted := r.SortedBySize()
+	err := sorted.Validate()
+	if err != nil {
+		return nil, err
+	}
+	points := sorted.DataPoints
+
+	first := points[0]
+	last := points[len(points)-1]
+	interpolated := new(Result)
+	for _, size := range sizes {
+		if math.IsNaN(size) || math.IsInf(size, 0) {
+			return nil, fmt.Errorf("invalid size %f", size)
+		}
+		var value float64
+		switch {
+		case size < first.Size || size > last.Size:
+			if !clamp {
+				return nil, fmt.Errorf("size %f is outside of the measured range [%f, %f]", size, first.Size, last.Size)
+			}
+			value = first.Value
+			if size > last.Size {
+				value = last.Value
+			}
+		default:
+			// Index of the first data point which size is not smaller than the requested size
+			idx := sort.Search(len(points), func(i int) bool { return points[i].Size >= size })
+			if points[idx].Size == size {
+				value = points[idx].Value
+			} else {
+				lower := points[idx-1]
+				upper := points[idx]
+				value = lower.Value + (size-lower.Size)*(upper.Value-lower.Value)/(upper.Size-lower.Size)
+			}
+		}
+		interpolated.DataPoints = append(interpolated.DataPoints, &DataPoint{Size: size, Value: value})
+	}
+	return interpolated, nil
+}
+
+// InterpolateAt returns a new result with the values linearly interpolated at the given sizes.
+// Sizes outside of the measured range are an error; see InterpolateAtClamped to clamp them instead.
+func (r *Result) InterpolateAt(sizes []float64) (*Result, error) {
+	return r.interpolateAt(sizes, false)
+}
+
+// InterpolateAtClamped is similar to InterpolateAt but sizes outside of the measured range get the value
+// of the closest measured size
+func (r *Result) InterpolateAtClamped(sizes []float64) (*Result, error) {
+	return r.interpolateAt(sizes, true)
+}
+
+// SortBySize sorts the data points by ascending size, in place
+func (r *Result) SortBySize() {
+	sort.SliceStable(r.DataPoints, func(i, j int) bool { return r.DataPoints[i].Size < r.DataPoints[j].Size })
+}
+
+// SortedBySize returns a new result with the data points sorted by ascending size; the result is not modified
+func (r *Result) SortedBySize() *Result {
+	sorted := r.Clone()
+	sorted.SortBySize()
+	return sorted
+}
+
+// Deduplicate returns a new result with a single data point per size. The strategy to resolve duplicate
+// sizes is "first", "last" or "mean". Data points are kept in the order of the first occurrence of their size.
+// With "first" and "last", the extra values of the selected data point are kept; with "mean", they are dropped.
+func (r *Result) Deduplicate(strategy string) (*Result, error) {
+	switch strategy {
+	case "first", "last", "mean":
+	default:
+		return nil, fmt.Errorf("unsupported deduplication strategy %q", strategy)
+	}
+
+	deduplicated := new(Result)
+	indexes := make(map[float64]int)
+	counts := make(map[float64]int)
+	for _, dp := range r.DataPoints {
+		idx, ok := indexes[dp.Size]
+		if !ok {
+			indexes[dp.Size] = len(deduplicated.DataPoints)
+			counts[dp.Size] = 1
+			deduplicatedDataPoint := dp.clone()
+			if strategy == "mean" {
+				deduplicatedDataPoint.Extra = nil
+			}
+			deduplicated.DataPoints = append(deduplicated.DataPoints, deduplicatedDataPoint)
+			continue
+		}
+
+		counts[dp.Size]++
+		switch strategy {
+		case "last":
+			deduplicated.DataPoints[idx] = dp.clone()
+		case "mean":
+			// Values are summed up here and divided once all the data points are known
+			deduplicated.DataPoints[idx].Value += dp.Value
+		}
+	}
+	if strategy == "mean" {
+		for _, dp := range deduplicated.DataPoints {
+			dp.Value /= float64(counts[dp.Size])
+		}
+	}
+	return deduplicated, nil
+}
+
+// TrimZeros returns a new result without the leading and trailing data points which value is 0;
+// zeros in between are kept and the result is not modified
+func (r *Result) TrimZeros() *Result {
+	start := 0
+	for start < len(r.DataPoints) && r.DataPoints[start].Value == 0 {
+		start++
+	}
+	end := len(r.DataPoints)
+	for end > start && r.DataPoints[end-1].Value == 0 {
+		end--
+	}
+	return (&Result{DataPoints: r.DataPoints[start:end]}).Clone()
+}
+
+// Filter returns a new result with only the data points for which pred returns true;
+// the order of the data points is preserved and the result is not modified
+func (r *Result) Filter(pred func(*DataPoint) bool) *Result {
+	filtered := new(Result)
+	for _, dp := range r.DataPoints {
+		if pred(dp) {
+			filtered.DataPoints = append(filtered.DataPoints, dp.clone())
+		}
+	}
+	return filtered
+}
+
+// MapValues returns a new result where each value is replaced by the value fn returns for the size and value
+// of the data point. The extra values, e.g., ExtraMin, are mapped the same way since they are in the same unit
+// as the value. The result is not modified.
+func (r *Result) MapValues(fn func(size, value float64) float64) *Result {
+	mapped := new(Result)
+	for _, dp := range r.DataPoints {
+		mappedDataPoint := dp.clone()
+		mappedDataPoint.Value = fn(dp.Size, dp.Value)
+		for k, v := range mappedDataPoint.Extra {
+			mappedDataPoint.Extra[k] = fn(dp.Size, v)
+		}
+		mapped.DataPoints = append(mapped.DataPoints, mappedDataPoint)
+	}
+	return mapped
+}
+
+// Select returns a new result where the value of each data point is the extra value with the given key,
+// e.g., ExtraMax; the result is not modified
+func (r *Result) Select(metric string) (*Result, error) {
+	selected := new(Result)
+	for _, dp := range r.DataPoints {
+		value, ok := dp.Extra[metric]
+		if !ok {
+			return nil, fmt.Errorf("no %s value for size %f", metric, dp.Size)
+		}
+		selectedDataPoint := dp.clone()
+		selectedDataPoint.Value = value
+		selected.DataPoints = append(selected.DataPoints, selectedDataPoint)
+	}
+	return selected, nil
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestInterpolateAt(t *testing.T) {
+	// Data points are deliberately not sorted by size
+	r := &Result{DataPoints: []*DataPoint{{Size: 4, Value: 40}, {Size: 1, Value: 10}, {Size: 2, Value: 30}}}
+
+	tests := []struct {
+		name      string
+		sizes     []float64
+		clamp     bool
+		expectErr bool
+		expected  []*DataPoint
+	}{
+		{
+			name:     "in range",
+			sizes:    []float64{1.5, 3},
+			expected: []*DataPoint{{Size: 1.5, Value: 20}, {Size: 3, Value: 35}},
+		},
+		{
+			name:     "exact hits",
+			sizes:    []float64{4, 1, 2},
+			expected: []*DataPoint{{Size: 4, Value: 40}, {Size: 1, Value: 10}, {Size: 2, Value: 30}},
+		},
+		{
+			name:      "below the measured range",
+			sizes:     []float64{0.5},
+			expectErr: true,
+		},
+		{
+			name:      "above the measured range",
+			sizes:     []float64{8},
+			expectErr: true,
+		},
+		{
+			name:     "clamped",
+			sizes:    []float64{0.5, 2, 8},
+			clamp:    true,
+			expected: []*DataPoint{{Size: 0.5, Value: 10}, {Size: 2, Value: 30}, {Size: 8, Value: 40}},
+		},
+		{
+			name:      "NaN size",
+			sizes:     []float64{math.NaN()},
+			expectErr: true,
+		},
+		{
+			name:      "clamped NaN size",
+			sizes:     []float64{math.NaN()},
+			clamp:     true,
+			expectErr: true,
+		},
+		{
+			name:      "clamped infinite size",
+			sizes:     []float64{math.Inf(1)},
+			clamp:     true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interpolate := r.InterpolateAt
+			if tt.clamp {
+				interpolate = r.InterpolateAtClamped
+			}
+			interpolated, err := interpolate(tt.sizes)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("interpolation succeeded, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("interpolation failed: %s", err)
+			}
+			if !reflect.DeepEqual(interpolated.DataPoints, tt.expected) {
+				t.Fatalf("interpolation returned unexpected data points")
+			}
+		})
+	}
+}
+
+func TestInterpolateAtInvalidResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *Result
+	}{
+		{
+			name:   "empty result",
+			result: &Result{},
+		},
+		{
+			name:   "duplicate sizes",
+			result: &Result{DataPoints: []*DataPoint{{Size: 1, Value: 10}, {Size: 1, Value: 20}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.result.InterpolateAt([]float64{1})
+			if err == nil {
+				t.Fatalf("InterpolateAt() succeeded, expected an error")
+			}
+		})
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	r := &Result{DataPoints: []*DataPoint{fullDataPoint(2, 4, 2, 8), {Size: 4, Value: 1}}}
+
+	mapped := r.MapValues(func(size, value float64) float64 { return size * value })
+	expected := []*DataPoint{fullDataPoint(2, 8, 4, 16), {Size: 4, Value: 4}}
+	if !reflect.DeepEqual(mapped.DataPoints, expected) {
+		t.Fatalf("MapValues() returned unexpected data points")
+	}
+	if r.DataPoints[0].Extra[ExtraMax] != 8 {
+		t.Fatalf("MapValues() modified the original result")
+	}
+}
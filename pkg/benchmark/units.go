@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+//
+// See LICENSE.txt for license information
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"math"
+)
+
+// sizeUnits maps the supported size units to their number of bytes
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+}
+
+const (
+	throughputDimension = "throughput"
+	timeDimension       = "time"
+)
+
+// valueUnit describes a unit of the values of a result
+type valueUnit struct {
+	// dimension is what the unit measures; only units with the same dimension can be converted
+	dimension string
+
+	// factor is the value of the unit in the base unit of the dimension (B/s or s)
+	factor float64
+}
+
+// valueUnits maps the supported value units to their description
+var valueUnits = map[string]valueUnit{
+	"B/s":   {throughputDimension, 1},
+	"KB/s":  {throughputDimension, 1e3},
+	"MB/s":  {throughputDimension, 1e6},
+	"GB/s":  {throughputDimension, 1e9},
+	"KiB/s": {throughputDimension, 1 << 10},
+	"MiB/s": {throughputDimension, 1 << 20},
+	"GiB/s": {throughputDimension, 1 << 30},
+	"ns":    {timeDimension, 1e-9},
+	"us":    {timeDimension, 1e-6},
+	"ms":    {timeDimension, 1e-3},
+	"s":     {timeDimension, 1},
+}
+
+// SizeIn returns the size of the data point, which is in bytes, in a given unit (B, KiB, MiB, GiB, KB, MB or GB)
+func (d *DataPoint) SizeIn(unit string) (float64, error) {
+	factor, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unsupported size unit %q", unit)
+	}
+	return d.Size / factor, nil
+}
+
+// ConvertSizes returns a new result with all the sizes converted to a given unit; the result is not modified
+func (r *Result) ConvertSizes(unit string) (*Result, error) {
+	if _, ok := sizeUnits[unit]; !ok {
+		return nil, fmt.Errorf("unsupported size unit %q", unit)
+	}
+
+	converted := new(Result)
+	for _, dp := range r.DataPoints {
+		size, err := dp.SizeIn(unit)
+		if err != nil {
+			return nil, err
+		}
+		convertedDataPoint := dp.clone()
+		convertedDataPoint.Size = size
+		converted.DataPoints = append(converted.DataPoints, convertedDataPoint)
+	}
+	return converted, nil
+}
+
+// ConvertValues returns a new result with all the values, including the extra values, converted from a unit
+// to another, e.g., from MB/s to GB/s or from us to ms; the result is not modified
+func (r *Result) ConvertValues(from, to string) (*Result, error) {
+	fromUnit, ok := valueUnits[from]
+	if !ok {
+		return nil, fmt.Errorf("unsupported value unit %q", from)
+	}
+	toUnit, ok := valueUnits[to]
+	if !ok {
+		return nil, fmt.Errorf("unsupported value unit %q", to)
+	}
+	if fromUnit.dimension != toUnit.dimension {
+		return nil, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, fromUnit.dimension, to, toUnit.dimension)
+	}
+
+	factor := fromUnit.factor / toUnit.factor
+	return r.MapValues(func(_, value float64) float64 { return value * factor }), nil
+}
+
+// DeriveBandwidth returns a new result with the bandwidth in MB/s derived from a result with sizes in bytes
+// and latencies in microseconds. Extra values are derived as well; since the maximum latency gives the
+// minimum bandwidth, the ExtraMin and ExtraMax values are swapped.
+func (r *Result) DeriveBandwidth() (*Result, error) {
+	for _, dp := range r.DataPoints {
+		if dp.Value == 0 {
+			return nil, fmt.Errorf("latency for size %f is 0", dp.Size)
+		}
+		for k, v := range dp.Extra {
+			if v == 0 {
+				return nil, fmt.Errorf("%s latency for size %f is 0", k, dp.Size)
+			}
+		}
+	}
+	// Bytes per microsecond are MB/s
+	bandwidth := r.MapValues(func(size, latency float64) float64 { return size / latency })
+	for _, dp := range bandwidth.DataPoints {
+		min, hasMin := dp.Extra[ExtraMin]
+		max, hasMax := dp.Extra[ExtraMax]
+		delete(dp.Extra, ExtraMin)
+		delete(dp.Extra, ExtraMax)
+		if hasMax {
+			dp.Extra[ExtraMin] = max
+		}
+		if hasMin {
+			dp.Extra[ExtraMax] = min
+		}
+	}
+	return bandwidth, nil
+}
+
+// SizeBytes returns the size of the data point as an integral number of bytes
+func (d *DataPoint) SizeBytes() (int64, error) {
+	if d.Size < 0 || d.Size != math.Trunc(d.Size) || d.Size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %f is not a valid number of bytes", d.Size)
+	}
+	return int64(d.Size), nil
+}